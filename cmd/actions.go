@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/forms"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/huh"
+)
+
+// Keys handled by the root picker in addition to Enter.
+const (
+	keyKill     = "ctrl-x"
+	keyTemplate = "ctrl-t"
+	keyRename   = "ctrl-r"
+	keyCopy     = "ctrl-y"
+)
+
+var pickerKeys = []string{keyKill, keyTemplate, keyRename, keyCopy}
+
+const pickerHeader = "enter: open · ctrl-x: kill · ctrl-t: template · ctrl-r: rename · ctrl-y: copy path"
+
+// pickResult holds the entries chosen in the root picker. Template is set when
+// they were opened with ctrl-t and overrides the usual layout resolution.
+type pickResult struct {
+	Choices  []string
+	Template *models.SessionTemplate
+}
+
+// pickEntry runs the root picker until entries are opened or the picker is
+// cancelled, in which case no choices are returned. With multi, several
+// entries can be marked. Actions bound to pickerKeys run in between and the
+// picker is reloaded afterwards, with the outcome shown in its header.
+func pickEntry(flagDepth int, multi bool) (pickResult, map[string]models.DirEntry, error) {
+	var status string
+	for {
+		entries, err := newBuilder().BuildEntries(flagDepth)
+		if err != nil {
+			return pickResult{}, nil, fmt.Errorf("failed to build directory entries: %w", err)
+		}
+
+		names := make([]string, 0, len(entries))
+		for name := range entries {
+			names = append(names, name)
+		}
+		sortEntryNames(names, entries)
+
+		opts, cleanup := entryPickerOptions(entries)
+		opts.Multi = multi
+		opts.Expect = pickerKeys
+		opts.Header = pickerHeader
+		if status != "" {
+			opts.Header = status + "\n" + pickerHeader
+		}
+
+		result, err := newPicker().Select(names, opts)
+		cleanup()
+		if err != nil {
+			if err.Error() == "user cancelled" {
+				return pickResult{}, entries, nil
+			}
+			return pickResult{}, nil, fmt.Errorf("selecting with fzf failed: %w", err)
+		}
+
+		if result.Selection == "" {
+			if result.Key == "" {
+				return pickResult{}, entries, nil
+			}
+			status = ""
+			continue
+		}
+
+		switch result.Key {
+		case "":
+			return pickResult{Choices: result.Selections}, entries, nil
+		case keyTemplate:
+			if !slices.ContainsFunc(result.Selections, func(label string) bool { return !isSessionEntry(label) }) {
+				status = "Templates only apply to new sessions"
+				continue
+			}
+			tmpl, err := selectTemplate()
+			if errors.Is(err, huh.ErrUserAborted) {
+				status = ""
+				continue
+			}
+			if err != nil {
+				return pickResult{}, nil, err
+			}
+			return pickResult{Choices: result.Selections, Template: &tmpl}, entries, nil
+		default:
+			status, err = runPickerAction(result.Key, result.Selections, entries)
+			if err != nil {
+				status = "Error: " + err.Error()
+			}
+		}
+	}
+}
+
+// runPickerAction performs the action bound to key on the selected picker
+// labels and returns a status message for the picker header. Killing applies
+// to every selected session; renaming and copying to the first selection.
+func runPickerAction(key string, labels []string, entries map[string]models.DirEntry) (string, error) {
+	if key == keyKill {
+		var killed []string
+		for _, label := range labels {
+			sessionName, isSession := strings.CutPrefix(label, cfg.Settings.TmuxSessionPrefix)
+			if !isSession {
+				continue
+			}
+			if err := tmux.KillSession(tmuxDriver, &cfg, sessionName); err != nil {
+				return "", err
+			}
+			killed = append(killed, sessionName)
+		}
+		if len(killed) == 0 {
+			return "Only active sessions can be killed", nil
+		}
+		return fmt.Sprintf("Killed session %s", strings.Join(killed, ", ")), nil
+	}
+
+	label := labels[0]
+	sessionName, isSession := strings.CutPrefix(label, cfg.Settings.TmuxSessionPrefix)
+
+	switch key {
+	case keyRename:
+		if !isSession {
+			return "Only active sessions can be renamed", nil
+		}
+		newName := sessionName
+		if err := renameForm(sessionName, &newName).Run(); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return "", nil
+			}
+			return "", err
+		}
+		if renamed, err := renameSession(sessionName, newName); err != nil || !renamed {
+			return "", err
+		}
+		return fmt.Sprintf("Renamed session %s to %s", sessionName, newName), nil
+
+	case keyCopy:
+		path := entries[label].Path
+		if isSession {
+			var err error
+			if path, err = tmuxDriver.SessionPath(sessionName); err != nil {
+				return "", err
+			}
+		}
+		return copyPath(path)
+	}
+
+	return "", fmt.Errorf("unknown picker key %q", key)
+}
+
+// copyPath copies path to the system clipboard, falling back to a tmux paste
+// buffer when no clipboard is available.
+func copyPath(path string) (string, error) {
+	err := clipboard.WriteAll(path)
+	if err == nil {
+		return fmt.Sprintf("Copied %s", path), nil
+	}
+	if !tmuxDriver.ServerRunning() {
+		return "", fmt.Errorf("copying to clipboard: %w", err)
+	}
+	if err := tmuxDriver.SetBuffer(path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Copied %s to the tmux paste buffer", path), nil
+}
+
+// selectTemplate asks the user to pick a template, preselecting the default.
+func selectTemplate() (models.SessionTemplate, error) {
+	var selectedIdx int
+	for i, tmpl := range cfg.Templates {
+		if tmpl.Default {
+			selectedIdx = i
+		}
+	}
+
+	if err := forms.TemplateSelectForm(cfg.Templates, &selectedIdx).Run(); err != nil {
+		return models.SessionTemplate{}, err
+	}
+	return cfg.Templates[selectedIdx], nil
+}
+
+// isSessionEntry reports whether the picker label is an active tmux session.
+func isSessionEntry(label string) bool {
+	return strings.HasPrefix(label, cfg.Settings.TmuxSessionPrefix)
+}
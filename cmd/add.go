@@ -63,6 +63,10 @@ func wouldBeFoundByScanDirs(targetPath string, scanDirs []models.ScanDir) (*mode
 
 		depth := scanDir.GetDepth(0, cfg.Settings.DefaultDepth)
 
+		if len(scanDir.Markers) > 0 && !utility.HasMarker(targetPath, scanDir.Markers) {
+			continue
+		}
+
 		if isWithinDepth(targetPath, resolvedScanPath, depth) {
 			return &scanDir, depth, true
 		}
@@ -26,7 +26,8 @@ Relative paths (like . or ..) will be converted to absolute paths.
 Examples:
   muxly add scan ~/Dev                      # Add with default depth
   muxly add s ~/projects --depth 2          # Scan 2 levels deep
-  muxly add scan ~/.config --depth 1 --alias config`,
+  muxly add scan ~/.config --depth 1 --alias config
+  muxly add scan ~/code --depth 5 --marker .git --marker go.mod --stop-at-marker`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		inputPath := args[0]
@@ -46,6 +47,11 @@ Examples:
 		// Get flags
 		depth, _ := cmd.Flags().GetInt("depth")
 		alias, _ := cmd.Flags().GetString("alias")
+		markers, _ := cmd.Flags().GetStringArray("marker")
+		stopAtMarker, _ := cmd.Flags().GetBool("stop-at-marker")
+		if stopAtMarker && len(markers) == 0 {
+			return fmt.Errorf("--stop-at-marker requires at least one --marker")
+		}
 
 		// Check if already in scan_dirs
 		for _, scanDir := range cfg.ScanDirs {
@@ -74,6 +80,9 @@ Examples:
 			newScanDir.Alias = alias
 		}
 
+		newScanDir.Markers = markers
+		newScanDir.StopAtMarker = stopAtMarker
+
 		// Add to scan_dirs and write config using viper
 		updatedScanDirs := append(cfg.ScanDirs, newScanDir)
 		viper.Set("scan_dirs", updatedScanDirs)
@@ -93,4 +102,6 @@ func init() {
 	// Flags for scan subcommand
 	addScanCmd.Flags().IntP("depth", "d", 0, "Scanning depth (0 = use default_depth)")
 	addScanCmd.Flags().StringP("alias", "a", "", "Alias prefix for the directory in selector")
+	addScanCmd.Flags().StringArrayP("marker", "m", nil, "Only list directories containing this file or directory (repeatable)")
+	addScanCmd.Flags().Bool("stop-at-marker", false, "Stop descending into a directory once it has a marker")
 }
@@ -4,18 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
-	"slices"
-	"strings"
 
 	"github.com/Pairadux/muxly/internal/forms"
-	"github.com/Pairadux/muxly/internal/fzf"
-	"github.com/Pairadux/muxly/internal/selector"
+	"github.com/Pairadux/muxly/internal/models"
 	"github.com/Pairadux/muxly/internal/tmux"
 	"github.com/Pairadux/muxly/internal/utility"
 
 	"github.com/spf13/cobra"
 )
 
+var createDetached bool
+
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a session from a template",
@@ -37,8 +36,7 @@ var createCmd = &cobra.Command{
 			}
 			sessionPath = resolved
 		} else {
-			builder := selector.NewBuilder(&cfg, verbose)
-			entries, err := builder.BuildEntries(0)
+			entries, err := newBuilder().BuildEntries(0)
 			if err != nil {
 				return fmt.Errorf("failed to build directory entries: %w", err)
 			}
@@ -48,25 +46,18 @@ var createCmd = &cobra.Command{
 				names = append(names, name)
 			}
 
-			slices.SortFunc(names, func(a, b string) int {
-				isTmuxA := strings.HasPrefix(a, cfg.Settings.TmuxSessionPrefix)
-				isTmuxB := strings.HasPrefix(b, cfg.Settings.TmuxSessionPrefix)
-				if isTmuxA && !isTmuxB {
-					return -1
-				}
-				if !isTmuxA && isTmuxB {
-					return 1
-				}
-				return strings.Compare(strings.ToLower(a), strings.ToLower(b))
-			})
+			sortEntryNames(names, entries)
 
-			choiceStr, err := fzf.SelectWithFzf(names)
+			opts, cleanup := entryPickerOptions(entries)
+			result, err := newPicker().Select(names, opts)
+			cleanup()
 			if err != nil {
 				if err.Error() == "user cancelled" {
 					return nil
 				}
 				return fmt.Errorf("selecting with fzf failed: %w", err)
 			}
+			choiceStr := result.Selection
 			if choiceStr == "" {
 				return nil
 			}
@@ -80,7 +71,16 @@ var createCmd = &cobra.Command{
 
 		sessionName := filepath.Base(sessionPath)
 
-		if err := tmux.CreateSessionFromTemplate(&cfg, tmpl, sessionPath, sessionName); err != nil {
+		if createDetached {
+			return startDetached(models.Session{
+				Name:     sessionName,
+				Path:     sessionPath,
+				Layout:   models.SessionLayout{Windows: tmpl.Windows},
+				Template: tmpl.Name,
+			})
+		}
+
+		if err := tmux.CreateSessionFromTemplate(tmuxDriver, &cfg, tmpl, sessionPath, sessionName); err != nil {
 			if errors.Is(err, tmux.ErrGracefulExit) {
 				return nil
 			}
@@ -92,4 +92,5 @@ var createCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(createCmd)
+	createCmd.Flags().BoolVar(&createDetached, "detached", false, "Create the session in the background and print its name instead of switching to it")
 }
@@ -19,6 +19,7 @@ Checks performed:
   • External dependencies (tmux, fzf, editor)
   • Configuration file validity
   • Directory accessibility
+  • .muxly files in all discovered project directories
 
 Exit codes:
   0 - All checks pass (warnings allowed)
@@ -31,10 +32,23 @@ func init() {
 	doctorCmd.Flags().BoolVarP(&doctorQuiet, "quiet", "q", false, "Only show warnings and errors")
 }
 
+// tmuxSocketLabel describes the configured tmux socket, or returns "" for the
+// default one.
+func tmuxSocketLabel() string {
+	if cfg.Settings.TmuxSocketPath != "" {
+		return cfg.Settings.TmuxSocketPath
+	}
+	if cfg.Settings.TmuxSocketName != "" {
+		return "-L " + cfg.Settings.TmuxSocketName
+	}
+	return ""
+}
+
 func runDoctor(cmd *cobra.Command, args []string) error {
 	var allResults []checks.CheckResult
 
-	externalResults := checks.CheckExternalUtils()
+	externalResults := checks.CheckExternalUtils(cfg.Settings.Picker)
+	externalResults = append(externalResults, checks.CheckTmuxServer(tmuxDriver, tmuxSocketLabel()))
 	externalResults = append(externalResults, checks.CheckEditor(cfg.Settings.Editor))
 	allResults = append(allResults, externalResults...)
 	fmt.Print(checks.FormatSection("External Dependencies", externalResults, doctorQuiet))
@@ -51,6 +65,14 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 		fmt.Print(checks.FormatSection("Directories", dirResults, doctorQuiet))
 	}
 
+	var projectDirs []string
+	for _, entry := range newBuilder().DirEntries(0) {
+		projectDirs = append(projectDirs, entry.Path)
+	}
+	muxlyResults := checks.CheckMuxlyFiles(projectDirs)
+	allResults = append(allResults, muxlyResults...)
+	fmt.Print(checks.FormatSection("Project Layouts", muxlyResults, doctorQuiet))
+
 	fmt.Println()
 	fmt.Println(checks.FormatSummary(allResults))
 
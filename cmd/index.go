@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/index"
+	"github.com/Pairadux/muxly/internal/selector"
+	"github.com/spf13/cobra"
+)
+
+// indexCmd represents the index command
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the cached project index",
+	Long: `Manage the cached project index
+
+Scan results for scan_dirs are cached in $XDG_CACHE_HOME/muxly/index.json and
+revalidated against directory modification times, so large trees are only
+walked again when something in them changed. Use --no-cache to bypass it.`,
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Discard the project index and rescan all scan_dirs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := index.DefaultPath()
+		if err != nil {
+			return fmt.Errorf("failed to locate project index: %w", err)
+		}
+
+		idx := index.New(path)
+		start := time.Now()
+		if _, err := selector.NewBuilder(&cfg, tmuxDriver, verbose).WithIndex(idx).BuildEntries(0); err != nil {
+			return fmt.Errorf("failed to build directory entries: %w", err)
+		}
+		if err := idx.Save(); err != nil {
+			return err
+		}
+
+		dirs := 0
+		for _, rec := range idx.Records {
+			dirs += len(rec.Dirs)
+		}
+		fmt.Printf("Indexed %d directories from %d scan root(s) in %s\n", dirs, len(idx.Records), time.Since(start).Round(time.Millisecond))
+		return nil
+	},
+}
+
+var indexStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show cached scan roots and whether they are fresh",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		idx, err := index.LoadDefault()
+		if err != nil {
+			return fmt.Errorf("failed to load project index: %w", err)
+		}
+
+		fmt.Printf("Index: %s\n", idx.Path())
+		records := idx.Sorted()
+		if len(records) == 0 {
+			fmt.Println("No cached scan roots. Run 'muxly index rebuild' or just 'muxly' to populate it.")
+			return nil
+		}
+
+		for _, rec := range records {
+			state := "fresh"
+			if !rec.Fresh() {
+				state = "stale"
+			}
+			markers := ""
+			if len(rec.Markers) > 0 {
+				markers = fmt.Sprintf(", markers: %s", strings.Join(rec.Markers, ", "))
+			}
+			fmt.Printf("  %-5s %s (depth %d%s): %d dir(s), scanned %s ago\n",
+				state, rec.Root, rec.Depth, markers, len(rec.Dirs), time.Since(rec.ScannedAt).Round(time.Second))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(indexCmd)
+	indexCmd.AddCommand(indexRebuildCmd)
+	indexCmd.AddCommand(indexStatusCmd)
+}
+
+// newBuilder returns a selector builder that uses the project index unless
+// --no-cache was given. Failing to load the index only disables caching.
+func newBuilder() *selector.Builder {
+	builder := selector.NewBuilder(&cfg, tmuxDriver, verbose)
+	if noCache {
+		return builder
+	}
+
+	idx, err := index.LoadDefault()
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Warning: project index unavailable: %v\n", err)
+		}
+		return builder
+	}
+	return builder.WithIndex(idx)
+}
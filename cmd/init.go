@@ -1,48 +1,94 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/Pairadux/muxly/internal/config"
 	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/forms"
 	"github.com/Pairadux/muxly/internal/models"
+
+	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
+// Template choices offered by the init wizard
+const (
+	initTemplatesBuiltin = "builtin"
+	initTemplatesCustom  = "custom"
+)
+
+var (
+	initForce  bool
+	initBackup bool
+)
+
 // initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Create a new config file",
 	Long: `Create a new config file
 
-Creates a config file at the specified location (default location if no argument passed) if no config file exists.
-Otherwise, the current config file is overwritten.`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// TODO: make an interactive menu for assigning these values
-		var configContent string
+Walks through the main settings: which of ~/Dev, ~/code, ~/src and ~/projects
+to scan and how deep, aliases for them, and which session templates to start
+with. tmux_base is taken from the running tmux server's base-index. The
+generated config is shown for confirmation before it is written.
+
+Without a terminal, or with --Defaults, the default config is written without
+prompting.
 
+An existing config file is only replaced with --force, or with --backup,
+which keeps a copy next to it. In the wizard you are offered the backup.
+
+Examples:
+  muxly config init
+  muxly config init --backup        # Start over, keeping the old config
+  muxly config init -D --force      # Reset to the defaults`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		useDefaults, err := cmd.Flags().GetBool("Defaults")
 		if err != nil {
 			return fmt.Errorf("failed to get Defaults flag: %w", err)
 		}
+		interactive := !useDefaults && term.IsTerminal(int(os.Stdin.Fd()))
 
-		if useDefaults {
-			configContent = generateConfigYAML(config.NewDefaultConfig())
+		backup, proceed, err := checkExistingConfig(interactive)
+		if err != nil || !proceed {
+			return err
 		}
 
-		// IDEA: before finalizing the changes, maybe diff the current file or show the config options setup and validate that they are correct
+		newCfg := config.NewDefaultConfig()
+		if interactive {
+			var confirmed bool
+			newCfg, confirmed, err = runInitWizard()
+			if err != nil || !confirmed {
+				return err
+			}
+		}
+
+		if backup {
+			backupPath := cfgFilePath + "." + time.Now().Format("20060102-150405") + ".bak"
+			if err := os.Rename(cfgFilePath, backupPath); err != nil {
+				return fmt.Errorf("failed to back up config: %w", err)
+			}
+			fmt.Println("Backed up existing config to", backupPath)
+		}
 
 		parent := filepath.Dir(cfgFilePath)
 		_ = os.MkdirAll(parent, constants.DirectoryPermissions)
 
-		if err := os.WriteFile(cfgFilePath, []byte(configContent), constants.FilePermissions); err != nil {
+		if err := os.WriteFile(cfgFilePath, []byte(generateConfigYAML(newCfg)), constants.FilePermissions); err != nil {
 			return fmt.Errorf("cannot write config: %w", err)
 		}
 
-		if verbose {
+		if interactive || verbose {
 			fmt.Println("Wrote config to", cfgFilePath)
 		}
 
@@ -52,13 +98,147 @@ Otherwise, the current config file is overwritten.`,
 
 func init() {
 	configCmd.AddCommand(initCmd)
-	// initCmd.Flags().IntP("tmux_base", "b", defaultTmuxBase, "What number your windows start ordering at.")
-	// initCmd.Flags().IntP("default_depth", "d", defaultDepth, "Default depth to scan.")
-	// initCmd.Flags().StringP("default_session", "D", defaultSession, "The name of the default session to fall back to.")
-	// initCmd.Flags().StringArrayP("scan_dirs", "s", scanDirsToStringArray(defaultScanDirs), "A list of paths that should always be scanned.\nConcat with :int for depth.")
-	// initCmd.Flags().StringArrayP("entry_dirs", "e", defaultEntryDirs, "A list of paths that are entries themselves.")
-	// initCmd.Flags().StringArrayP("ignore_dirs", "i", defaultIgnoreDirs, "A list of paths that should be removed.")
-	initCmd.Flags().BoolP("Defaults", "D", true /* FIXME: change to false once interactive prompt is completed */, "Accept all defaults. (No interactive prompt)")
+	initCmd.Flags().BoolP("Defaults", "D", false, "Accept all defaults. (No interactive prompt)")
+	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Overwrite an existing config file")
+	initCmd.Flags().BoolVarP(&initBackup, "backup", "b", false, "Keep a timestamped copy of an existing config file before replacing it")
+	initCmd.MarkFlagsMutuallyExclusive("force", "backup")
+}
+
+// checkExistingConfig decides what happens to an existing config file:
+// replaced outright with --force, backed up first with --backup or when the
+// user agrees to it, and left alone otherwise. proceed is false when the user
+// chose to keep the file.
+func checkExistingConfig(interactive bool) (backup, proceed bool, err error) {
+	if _, err := os.Stat(cfgFilePath); errors.Is(err, os.ErrNotExist) {
+		return false, true, nil
+	} else if err != nil {
+		return false, false, fmt.Errorf("failed to access %s: %w", cfgFilePath, err)
+	}
+
+	switch {
+	case initForce:
+		return false, true, nil
+	case initBackup:
+		return true, true, nil
+	case !interactive:
+		return false, false, fmt.Errorf("config already exists at %s\nUse --force to overwrite it or --backup to keep a copy", cfgFilePath)
+	}
+
+	form := forms.ConfirmationForm(
+		"A config already exists",
+		fmt.Sprintf("Back up %s and create a new one?", cfgFilePath),
+		&backup,
+	)
+	if err := form.Run(); err != nil {
+		return false, false, fmt.Errorf("failed to run confirmation form: %w", err)
+	}
+	if !backup {
+		fmt.Println("Kept the existing config.")
+	}
+	return backup, backup, nil
+}
+
+// initAnswers holds the choices made in the init wizard.
+type initAnswers struct {
+	roots     []string
+	aliases   []string
+	depth     int
+	templates string
+	editorCmd string
+	testCmd   string
+	tmuxBase  int
+}
+
+// config builds the config for the answers on top of the defaults.
+func (a initAnswers) config() models.Config {
+	c := config.NewDefaultConfig()
+	c.Settings.DefaultDepth = a.depth
+	c.Settings.TmuxBase = a.tmuxBase
+
+	c.ScanDirs = nil
+	for i, root := range a.roots {
+		c.ScanDirs = append(c.ScanDirs, models.ScanDir{Path: root, Alias: strings.TrimSpace(a.aliases[i])})
+	}
+
+	if a.templates == initTemplatesCustom {
+		c.Templates = []models.SessionTemplate{{
+			Name:    "dev",
+			Label:   "Editor + Shell + Tests",
+			Default: true,
+			Windows: []models.Window{
+				{Name: "editor", Cmd: strings.TrimSpace(a.editorCmd)},
+				{Name: "shell"},
+				{Name: "tests", Cmd: strings.TrimSpace(a.testCmd)},
+			},
+		}}
+	}
+	return c
+}
+
+// runInitWizard asks for the config's main settings and shows the result.
+// confirmed is false when the user declines to write it.
+func runInitWizard() (models.Config, bool, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return models.Config{}, false, fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	answers := initAnswers{depth: config.DefaultScanDepth, templates: initTemplatesBuiltin}
+
+	if roots := config.DetectProjectRoots(home); len(roots) > 0 {
+		options := make([]huh.Option[string], len(roots))
+		for i, root := range roots {
+			options[i] = huh.NewOption(fmt.Sprintf("%s (%d dir(s))", root.Path, root.Dirs), root.Path).Selected(true)
+		}
+		if err := forms.ScanDirsForm(options, &answers.roots, &answers.depth).Run(); err != nil {
+			return models.Config{}, false, fmt.Errorf("scan directory form failed: %w", err)
+		}
+	} else {
+		fmt.Println("No project directories found (looked for ~/Dev, ~/code, ~/src and ~/projects).")
+		fmt.Println("Add some later with 'muxly add scan <dir>'.")
+	}
+
+	answers.aliases = make([]string, len(answers.roots))
+	if len(answers.roots) > 0 {
+		if err := forms.AliasesForm(answers.roots, answers.aliases).Run(); err != nil {
+			return models.Config{}, false, fmt.Errorf("alias form failed: %w", err)
+		}
+	}
+
+	answers.editorCmd = cmp.Or(os.Getenv(constants.EnvEditor), config.DefaultEditor)
+	templateOptions := []huh.Option[string]{
+		huh.NewOption("Built-in: editor + terminal, single window, quick session", initTemplatesBuiltin),
+		huh.NewOption("Custom: editor, shell and tests windows", initTemplatesCustom),
+	}
+	form := forms.TemplateChoiceForm(templateOptions, &answers.templates, initTemplatesCustom, &answers.editorCmd, &answers.testCmd)
+	if err := form.Run(); err != nil {
+		return models.Config{}, false, fmt.Errorf("template form failed: %w", err)
+	}
+
+	base, err := tmuxDriver.BaseIndex()
+	if err != nil {
+		base = config.DefaultTmuxBase
+		fmt.Printf("Could not read base-index from tmux, using tmux_base: %d. Adjust it if your tmux.conf sets base-index.\n", base)
+	}
+	answers.tmuxBase = base
+
+	newCfg := answers.config()
+	if err := config.Validate(&newCfg); err != nil {
+		return models.Config{}, false, fmt.Errorf("generated config is invalid: %w", err)
+	}
+
+	fmt.Println()
+	fmt.Println(generateConfigYAML(newCfg))
+
+	var confirmed bool
+	confirm := forms.ConfirmationForm("Write this config?", cfgFilePath, &confirmed)
+	if err := confirm.Run(); err != nil {
+		return models.Config{}, false, fmt.Errorf("failed to run confirmation form: %w", err)
+	}
+	if !confirmed {
+		fmt.Println("Nothing written.")
+	}
+	return newCfg, confirmed, nil
 }
 
 func generateConfigYAML(cfg models.Config) string {
@@ -70,6 +250,10 @@ func generateConfigYAML(cfg models.Config) string {
 #              depth: 2
 #              alias: config
 #              template: minimal
+#            - path: ~/code
+#              depth: 5
+#              markers: [.git, go.mod]   # only list project roots
+#              stop_at_marker: true      # don't descend into projects
 #
 # entry_dirs: Additional directories always included (not scanned)
 #   Supports optional template assignment:
@@ -88,6 +272,22 @@ func generateConfigYAML(cfg models.Config) string {
 #   default: Mark exactly one template as the default (required on one template)
 #   path: Fixed working directory (optional, uses fzf picker if omitted)
 #   windows: List of windows to create with optional commands
+#     path: Working directory, relative to the session directory (optional)
+#     layout: tmux layout for the window (main-vertical, tiled, ... or a raw layout string)
+#     panes: Additional panes (split: horizontal|vertical, size: percent, path, cmd)
+#   hooks: Hooks for sessions created from this template (see hooks below)
+#
+# workspaces: Groups of sessions started and stopped together (optional)
+#   Example: - name: daily
+#              members:
+#                - entry: api        # session name of a configured project
+#                  template: go      # optional template override
+#                - entry: ~/notes    # or a directory path
+#              group: true           # share the first session's windows (optional)
+#
+# hooks: Shell commands run for every session (optional)
+#   on_create, on_attach, on_detach, on_kill: run with sh -c in the session
+#   directory, with MUXLY_SESSION, MUXLY_PATH and MUXLY_TEMPLATE set
 #
 # settings: General application settings
 #   editor: Default editor for 'muxly config edit' (overrides $EDITOR)
@@ -95,6 +295,14 @@ func generateConfigYAML(cfg models.Config) string {
 #   default_depth: Default scanning depth for scan_dirs without explicit depth
 #   tmux_session_prefix: Prefix for active tmux sessions in the selector
 #   always_kill_on_last_session: Skip prompt and kill server on last session
+#   sort_order: Picker ordering (alphabetical, recent, or frecency)
+#   display_mode: Picker labels (suffix, basename, home, or absolute)
+#   picker: Picker backend (fzf, builtin, or auto to use fzf when installed)
+#   tmux_socket_name: Use a separate tmux server by socket name (like tmux -L)
+#   tmux_socket_path: Use a separate tmux server by socket path (like tmux -S)
+#   fzf: Picker options (prompt, height, layout, border, color, args, tmux popup)
+#   hook_timeout: How long each hook command may run (default 30s)
+#   muxly_file_policy: Untrusted .muxly files with commands (prompt, ignore, or allow)
 
 `
 	yamlData, err := yaml.Marshal(cfg)
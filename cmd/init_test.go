@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+func TestInitAnswersConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		answers      initAnswers
+		wantScanDirs []models.ScanDir
+		wantDefault  string
+	}{
+		{
+			name:        "built-in templates without scan dirs",
+			answers:     initAnswers{depth: 1, templates: initTemplatesBuiltin},
+			wantDefault: "default",
+		},
+		{
+			name: "custom layout with aliased scan dirs",
+			answers: initAnswers{
+				roots:     []string{"~/Dev", "~/code"},
+				aliases:   []string{" dev ", ""},
+				depth:     2,
+				templates: initTemplatesCustom,
+				editorCmd: "nvim",
+				testCmd:   "go test ./...",
+				tmuxBase:  1,
+			},
+			wantScanDirs: []models.ScanDir{{Path: "~/Dev", Alias: "dev"}, {Path: "~/code"}},
+			wantDefault:  "dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.answers.config()
+			if err := config.Validate(&got); err != nil {
+				t.Fatalf("generated config is invalid: %v", err)
+			}
+
+			if len(got.ScanDirs) != len(tt.wantScanDirs) {
+				t.Fatalf("ScanDirs = %+v, want %+v", got.ScanDirs, tt.wantScanDirs)
+			}
+			for i, sd := range got.ScanDirs {
+				if sd.Path != tt.wantScanDirs[i].Path || sd.Alias != tt.wantScanDirs[i].Alias {
+					t.Errorf("ScanDirs[%d] = %+v, want %+v", i, sd, tt.wantScanDirs[i])
+				}
+			}
+			if tmpl, _ := config.DefaultTemplate(&got); tmpl.Name != tt.wantDefault {
+				t.Errorf("default template = %q, want %q", tmpl.Name, tt.wantDefault)
+			}
+			if got.Settings.DefaultDepth != tt.answers.depth || got.Settings.TmuxBase != tt.answers.tmuxBase {
+				t.Errorf("Settings = %+v, want depth %d and tmux_base %d", got.Settings, tt.answers.depth, tt.answers.tmuxBase)
+			}
+		})
+	}
+}
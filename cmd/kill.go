@@ -5,13 +5,15 @@ import (
 	"fmt"
 
 	"github.com/Pairadux/muxly/internal/forms"
-	"github.com/Pairadux/muxly/internal/fzf"
 	"github.com/Pairadux/muxly/internal/tmux"
 
 	"github.com/spf13/cobra"
 )
 
-var killServer bool
+var (
+	killServer bool
+	killMulti  bool
+)
 
 // killCmd represents the kill command
 var killCmd = &cobra.Command{
@@ -21,23 +23,30 @@ var killCmd = &cobra.Command{
 
 If SESSION is provided, the current session is killed and the client switches to SESSION.
 Otherwise, a picker list of active sessions is displayed to choose a replacement.
-If no other sessions exist, a new session is created from the default template or the tmux server is killed.`,
+If no other sessions exist, a new session is created from the default template or the tmux server is killed.
+
+With --multi, any number of sessions can be marked and killed at once. If the
+current session is among them, it is handled as above once the others are gone.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if !tmux.IsTmuxServerRunning() {
+		if !tmuxDriver.ServerRunning() {
 			fmt.Println("No tmux server running. No changes made.")
 			return nil
 		}
 
 		if killServer {
-			if err := tmux.KillServer(); err != nil {
+			if err := tmux.KillServer(tmuxDriver, &cfg); err != nil {
 				return fmt.Errorf("failed to kill tmux server: %w", err)
 			}
 			fmt.Println("Tmux server killed.")
 			return nil
 		}
 
-		currentSession := tmux.GetCurrentTmuxSession()
+		currentSession := tmuxDriver.CurrentSession()
+		if killMulti {
+			return killSelectedSessions(currentSession)
+		}
+
 		if currentSession == "" {
 			if !killServer {
 				form := forms.ConfirmationForm("Kill tmux server?", "This will terminate all tmux sessions.", &killServer)
@@ -53,86 +62,138 @@ If no other sessions exist, a new session is created from the default template o
 				return nil
 			}
 
-			if err := tmux.KillServer(); err != nil {
+			if err := tmux.KillServer(tmuxDriver, &cfg); err != nil {
 				return fmt.Errorf("failed to kill tmux server: %w", err)
 			}
 			fmt.Println("Tmux server killed.")
 			return nil
 		}
 
-		var choiceStr string
+		var target string
 		if len(args) == 1 {
-			choiceStr = args[0]
+			target = args[0]
 		}
-		if choiceStr == "" && !killServer {
-			otherSessions := tmux.GetSessionsExceptCurrent(currentSession)
-
-			// IDEA: add config option to allow users to create new session rather than dropping back to existing one on kill
-			// might even just make this the default behavior...
-			if len(otherSessions) == 0 {
-				if cfg.Settings.AlwaysKillOnLastSession {
-					if err := tmux.KillServer(); err != nil {
-						return fmt.Errorf("failed to kill tmux server: %w", err)
-					}
-					fmt.Println("Tmux server killed.")
-					return nil
-				}
-
-				var createFromTemplate bool
-				form := forms.ConfirmationForm("Create session from default template?", "Declining will kill the tmux server.", &createFromTemplate)
+		return killCurrentSession(currentSession, target)
+	},
+}
 
-				if err := form.Run(); err != nil {
-					return fmt.Errorf("failed to run confirmation form: %w", err)
-				}
+// killCurrentSession switches the client to target, or to a session picked
+// from the remaining ones when target is empty, and kills currentSession.
+// When no other session exists, killLastSession takes over.
+func killCurrentSession(currentSession, target string) error {
+	if target == "" {
+		otherSessions := tmux.GetSessionsExceptCurrent(tmuxDriver, currentSession)
+
+		// IDEA: add config option to allow users to create new session rather than dropping back to existing one on kill
+		// might even just make this the default behavior...
+		if len(otherSessions) == 0 {
+			return killLastSession(currentSession)
+		}
 
-				if createFromTemplate {
-					if err := tmux.CreateSessionFromDefaultTemplate(&cfg); err != nil {
-						if errors.Is(err, tmux.ErrGracefulExit) {
-							return nil
-						}
-						return fmt.Errorf("failed to create session from default template: %w", err)
-					}
-					if err := tmux.KillSession(currentSession); err != nil {
-						return fmt.Errorf("failed to kill session: %w", err)
-					}
-				} else {
-					if err := tmux.KillServer(); err != nil {
-						return fmt.Errorf("failed to kill tmux server: %w", err)
-					}
-					fmt.Println("Tmux server killed.")
-				}
+		result, err := newPicker().Select(otherSessions, pickerOptions("--session"))
+		if err != nil {
+			if err.Error() == "user cancelled" {
+				return nil
 			}
+			return fmt.Errorf("selecting with fzf failed: %w", err)
+		}
 
-			var err error
-			choiceStr, err = fzf.SelectWithFzf(otherSessions)
-			if err != nil {
-				if err.Error() == "user cancelled" {
-					return nil
-				}
-				return fmt.Errorf("selecting with fzf failed: %w", err)
-			}
+		target = result.Selection
+		if target == "" {
+			return nil
+		}
+	}
 
-			if choiceStr == "" {
-				return nil
-			}
+	if err := tmux.SwitchToExistingSession(tmuxDriver, &cfg, target); err != nil {
+		if errors.Is(err, tmux.ErrGracefulExit) {
+			return nil
 		}
-		sessionName := choiceStr
-		if err := tmux.SwitchToExistingSession(&cfg, sessionName); err != nil {
-			if errors.Is(err, tmux.ErrGracefulExit) {
-				return nil
-			}
-			return fmt.Errorf("Failed to switch session: %w", err)
+		return fmt.Errorf("Failed to switch session: %w", err)
+	}
+
+	if err := tmux.KillSession(tmuxDriver, &cfg, currentSession); err != nil {
+		return fmt.Errorf("Failed to kill session: %w", err)
+	}
+
+	return nil
+}
+
+// killLastSession handles killing the only remaining session: it either
+// replaces it with a session from the default template or kills the tmux
+// server, asking unless always_kill_on_last_session is set.
+func killLastSession(currentSession string) error {
+	if cfg.Settings.AlwaysKillOnLastSession {
+		if err := tmux.KillServer(tmuxDriver, &cfg); err != nil {
+			return fmt.Errorf("failed to kill tmux server: %w", err)
 		}
+		fmt.Println("Tmux server killed.")
+		return nil
+	}
+
+	var createFromTemplate bool
+	form := forms.ConfirmationForm("Create session from default template?", "Declining will kill the tmux server.", &createFromTemplate)
+
+	if err := form.Run(); err != nil {
+		return fmt.Errorf("failed to run confirmation form: %w", err)
+	}
 
-		if err := tmux.KillSession(currentSession); err != nil {
-			return fmt.Errorf("Failed to kill session: %w", err)
+	if !createFromTemplate {
+		if err := tmux.KillServer(tmuxDriver, &cfg); err != nil {
+			return fmt.Errorf("failed to kill tmux server: %w", err)
 		}
+		fmt.Println("Tmux server killed.")
+		return nil
+	}
 
+	if err := tmux.CreateSessionFromDefaultTemplate(tmuxDriver, &cfg); err != nil {
+		if errors.Is(err, tmux.ErrGracefulExit) {
+			return nil
+		}
+		return fmt.Errorf("failed to create session from default template: %w", err)
+	}
+	if err := tmux.KillSession(tmuxDriver, &cfg, currentSession); err != nil {
+		return fmt.Errorf("failed to kill session: %w", err)
+	}
+
+	return nil
+}
+
+// killSelectedSessions lets the user mark several sessions and kills them.
+// The current session, if marked, is killed last through killCurrentSession
+// so the client is moved elsewhere first.
+func killSelectedSessions(currentSession string) error {
+	sessions := tmux.GetTmuxSessionNames(tmuxDriver)
+	opts := pickerOptions("--session")
+	opts.Multi = true
+
+	result, err := newPicker().Select(sessions, opts)
+	if err != nil {
+		if err.Error() == "user cancelled" {
+			return nil
+		}
+		return fmt.Errorf("selecting with fzf failed: %w", err)
+	}
+
+	killCurrent := false
+	for _, name := range result.Selections {
+		if name == currentSession {
+			killCurrent = true
+			continue
+		}
+		if err := tmux.KillSession(tmuxDriver, &cfg, name); err != nil {
+			return fmt.Errorf("failed to kill session %q: %w", name, err)
+		}
+		fmt.Printf("Killed session %s\n", name)
+	}
+
+	if !killCurrent {
 		return nil
-	},
+	}
+	return killCurrentSession(currentSession, "")
 }
 
 func init() {
 	rootCmd.AddCommand(killCmd)
-	rootCmd.PersistentFlags().BoolVarP(&killServer, "kill-server", "s", false, "Kill tmux server (rather than current session)")
+	killCmd.Flags().BoolVarP(&killServer, "kill-server", "s", false, "Kill tmux server (rather than current session)")
+	killCmd.Flags().BoolVarP(&killMulti, "multi", "m", false, "Select several sessions (tab to mark) and kill them all")
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+// useFakeTmux points the commands at a fake tmux server running the named
+// sessions, with the client attached to current.
+func useFakeTmux(t *testing.T, current string, sessions ...string) *tmux.FakeDriver {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	driver := tmux.NewFakeDriver(sessions...)
+	driver.Current = current
+
+	prevDriver, prevCfg := tmuxDriver, cfg
+	t.Cleanup(func() { tmuxDriver, cfg = prevDriver, prevCfg })
+	tmuxDriver = driver
+	cfg = models.Config{Settings: models.Settings{TmuxBase: -1}}
+
+	return driver
+}
+
+func TestKillCurrentSessionWithTarget(t *testing.T) {
+	driver := useFakeTmux(t, "api", "api", "web")
+
+	if err := killCurrentSession("api", "web"); err != nil {
+		t.Fatalf("killCurrentSession() error = %v", err)
+	}
+
+	if want := []string{"web"}; !slices.Equal(driver.Switches, want) {
+		t.Errorf("Switches = %v, want %v", driver.Switches, want)
+	}
+	if got, want := tmux.GetTmuxSessionNames(driver), []string{"web"}; !slices.Equal(got, want) {
+		t.Errorf("sessions = %v, want %v", got, want)
+	}
+}
+
+func TestKillCurrentSessionKeepsSessionWhenSwitchFails(t *testing.T) {
+	driver := useFakeTmux(t, "api", "api", "web")
+	driver.Errors = map[string]error{"SwitchClient": errors.New("no client")}
+
+	if err := killCurrentSession("api", "web"); err == nil {
+		t.Fatal("killCurrentSession() succeeded although the switch failed")
+	}
+	if !driver.HasSession("api") {
+		t.Error("current session was killed although the client could not leave it")
+	}
+}
+
+func TestKillLastSessionKillsServer(t *testing.T) {
+	driver := useFakeTmux(t, "api", "api")
+	cfg.Settings.AlwaysKillOnLastSession = true
+
+	if err := killCurrentSession("api", ""); err != nil {
+		t.Fatalf("killCurrentSession() error = %v", err)
+	}
+	if driver.ServerRunning() {
+		t.Errorf("server still runs sessions %v", tmux.GetTmuxSessionNames(driver))
+	}
+}
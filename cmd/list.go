@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats for list --format
+const (
+	listFormatTable = "table"
+	listFormatJSON  = "json"
+	listFormatTSV   = "tsv"
+	listFormatPlain = "plain"
+)
+
+var listFormats = []string{listFormatTable, listFormatJSON, listFormatTSV, listFormatPlain}
+
+// sourceSession is the source of an entry that is a running tmux session
+// outside scan_dirs and entry_dirs.
+const sourceSession = "session"
+
+var (
+	listFormat       string
+	listSessionsOnly bool
+	listDirsOnly     bool
+	listTemplate     string
+)
+
+// listEntry is one entry of the picker as printed by list.
+type listEntry struct {
+	// Name is the label shown in the picker, which muxly also accepts as
+	// its SESSION argument.
+	Name     string `json:"name"`
+	Session  string `json:"session"`
+	Path     string `json:"path"`
+	Source   string `json:"source"`
+	Alias    string `json:"alias"`
+	Template string `json:"template"`
+	Running  bool   `json:"running"`
+	Attached int    `json:"attached"`
+	Windows  int    `json:"windows"`
+}
+
+// listCmd prints the entries the picker would offer
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "Print the entries the picker would offer",
+	Long: `Print the entries the picker would offer, in picker order, for scripts
+and other pickers.
+
+Each entry has its picker label (name), the tmux session it opens, its
+directory, where it comes from (scan_dir, entry_dir, or session for a tmux
+session outside both), the scan_dir alias, its template, and for running
+sessions the number of windows and attached clients. The template is the
+one configured for the directory, or the one a running session was created
+from.
+
+Formats:
+  table   Aligned columns with a header (the default)
+  json    An array of objects
+  tsv     Tab-separated columns as in table, without a header
+  plain   Only the names, one per line
+
+Examples:
+  muxly list
+  muxly list --format json | jq -r '.[] | select(.running) | .session'
+  muxly "$(muxly list -f plain | rofi -dmenu)"
+  muxly list --dirs-only --template go`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains(listFormats, listFormat) {
+			return fmt.Errorf("invalid --format %q (use %s)", listFormat, strings.Join(listFormats, ", "))
+		}
+		if listTemplate != "" {
+			if _, found := config.FindTemplateByName(&cfg, listTemplate); !found {
+				return fmt.Errorf("template %q not found", listTemplate)
+			}
+		}
+
+		entries, err := listEntries()
+		if err != nil {
+			return err
+		}
+
+		entries = slices.DeleteFunc(entries, func(e listEntry) bool {
+			return (listSessionsOnly && !e.Running) ||
+				(listDirsOnly && e.Source == sourceSession) ||
+				(listTemplate != "" && e.Template != listTemplate)
+		})
+
+		return writeListEntries(os.Stdout, entries, listFormat)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", listFormatTable, "Output format: table, json, tsv or plain")
+	listCmd.Flags().BoolVarP(&listSessionsOnly, "sessions-only", "s", false, "Only list running tmux sessions")
+	listCmd.Flags().BoolVarP(&listDirsOnly, "dirs-only", "d", false, "Only list directories from scan_dirs and entry_dirs")
+	listCmd.Flags().StringVarP(&listTemplate, "template", "t", "", "Only list entries using this template")
+	listCmd.MarkFlagsMutuallyExclusive("sessions-only", "dirs-only")
+	listCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(listFormats, cobra.ShellCompDirectiveNoFileComp))
+	listCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+}
+
+// listEntries returns the picker's entries in picker order. Running sessions
+// that belong to a directory keep that directory's source, alias and
+// template.
+func listEntries() ([]listEntry, error) {
+	builder := newBuilder()
+	entries, err := builder.BuildEntries(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build directory entries: %w", err)
+	}
+
+	dirs := make(map[string]models.DirEntry)
+	for _, dir := range builder.DirEntries(0) {
+		dirs[dir.SessionName] = dir
+	}
+
+	sessions, err := tmuxDriver.ListSessionInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+	running := make(map[string]tmux.SessionInfo, len(sessions))
+	for _, s := range sessions {
+		running[s.Name] = s
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sortEntryNames(names, entries)
+
+	result := make([]listEntry, 0, len(names))
+	for _, name := range names {
+		entry := entries[name]
+		item := listEntry{
+			Name:     name,
+			Session:  entry.SessionName,
+			Path:     entry.Path,
+			Source:   entry.Source,
+			Alias:    entry.Prefix,
+			Template: entry.Template,
+		}
+
+		if entry.Source == "" {
+			item.Source = sourceSession
+			if dir, ok := dirs[entry.SessionName]; ok {
+				item.Source, item.Alias, item.Template = dir.Source, dir.Prefix, dir.Template
+			}
+			if tmpl, err := tmuxDriver.SessionOption(entry.SessionName, tmux.TemplateOption); err == nil && tmpl != "" {
+				item.Template = tmpl
+			}
+		}
+		if info, ok := running[entry.SessionName]; ok {
+			item.Running = true
+			item.Path = info.Path
+			item.Attached = info.Attached
+			item.Windows = info.Windows
+		}
+
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// writeListEntries prints entries to w in the given format.
+func writeListEntries(w io.Writer, entries []listEntry, format string) error {
+	switch format {
+	case listFormatJSON:
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+
+	case listFormatPlain:
+		for _, e := range entries {
+			fmt.Fprintln(w, e.Name)
+		}
+		return nil
+
+	case listFormatTSV:
+		for _, e := range entries {
+			fmt.Fprintln(w, strings.Join(e.columns(), "\t"))
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSESSION\tSOURCE\tALIAS\tTEMPLATE\tRUNNING\tATTACHED\tWINDOWS\tPATH")
+	for _, e := range entries {
+		columns := e.columns()
+		for i, c := range columns {
+			if c == "" {
+				columns[i] = "-"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	}
+	return tw.Flush()
+}
+
+// columns returns the entry's fields in table and tsv column order.
+func (e listEntry) columns() []string {
+	return []string{
+		e.Name,
+		e.Session,
+		e.Source,
+		e.Alias,
+		e.Template,
+		strconv.FormatBool(e.Running),
+		strconv.Itoa(e.Attached),
+		strconv.Itoa(e.Windows),
+		e.Path,
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestListEntries(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	driver := useFakeTmux(t, "notes", "notes")
+
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg.ScanDirs = []models.ScanDir{{Path: root, Alias: "dev", Template: "go"}}
+	cfg.Settings.TmuxSessionPrefix = "[TMUX] "
+	driver.Sessions = append(driver.Sessions, tmux.FakeSession{
+		Name:    "api",
+		Path:    filepath.Join(root, "api"),
+		Windows: []models.Window{{Name: "editor"}, {Name: "shell"}},
+		Options: map[string]string{tmux.TemplateOption: "rust"},
+	}, tmux.FakeSession{Name: "scratch", Path: "/tmp"})
+
+	entries, err := listEntries()
+	if err != nil {
+		t.Fatalf("listEntries() error = %v", err)
+	}
+
+	want := []listEntry{
+		{Name: "[TMUX] api", Session: "api", Path: filepath.Join(root, "api"), Source: "scan_dir", Alias: "dev", Template: "rust", Running: true, Windows: 2},
+		{Name: "[TMUX] scratch", Session: "scratch", Path: "/tmp", Source: "session", Running: true},
+		{Name: "web", Session: "web", Path: filepath.Join(root, "web"), Source: "scan_dir", Alias: "dev", Template: "go"},
+	}
+	if !slices.Equal(entries, want) {
+		t.Errorf("listEntries() =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestWriteListEntries(t *testing.T) {
+	entries := []listEntry{
+		{Name: "[TMUX] api", Session: "api", Path: "/code/api", Source: "entry_dir", Running: true, Attached: 1, Windows: 2},
+		{Name: "web", Session: "web", Path: "/code/web", Source: "scan_dir", Template: "go"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: listFormatPlain,
+			want:   "[TMUX] api\nweb\n",
+		},
+		{
+			format: listFormatTSV,
+			want: "[TMUX] api\tapi\tentry_dir\t\t\ttrue\t1\t2\t/code/api\n" +
+				"web\tweb\tscan_dir\t\tgo\tfalse\t0\t0\t/code/web\n",
+		},
+		{
+			format: listFormatTable,
+			want: "NAME        SESSION  SOURCE     ALIAS  TEMPLATE  RUNNING  ATTACHED  WINDOWS  PATH\n" +
+				"[TMUX] api  api      entry_dir  -      -         true     1         2        /code/api\n" +
+				"web         web      scan_dir   -      go        false    0         0        /code/web\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeListEntries(&buf, entries, tt.format); err != nil {
+				t.Fatalf("writeListEntries() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("writeListEntries() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run(listFormatJSON, func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeListEntries(&buf, entries, listFormatJSON); err != nil {
+			t.Fatalf("writeListEntries() error = %v", err)
+		}
+		var got []listEntry
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+		}
+		if !slices.Equal(got, entries) {
+			t.Errorf("decoded %+v, want %+v", got, entries)
+		}
+	})
+
+	var buf bytes.Buffer
+	if err := writeListEntries(&buf, []listEntry{}, listFormatJSON); err != nil || buf.String() != "[]\n" {
+		t.Errorf("writeListEntries() of no entries = %q, %v; want an empty array", buf.String(), err)
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/selector"
+	"github.com/Pairadux/muxly/internal/tmux"
+	"github.com/Pairadux/muxly/internal/utility"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	openName     string
+	openTemplate string
+	openDetached bool
+)
+
+// openCmd opens a session for any directory
+var openCmd = &cobra.Command{
+	Use:   "open [PATH]",
+	Short: "Open a session for any directory",
+	Long: `Open a session for any directory, whether or not it is in scan_dirs or
+entry_dirs. PATH defaults to the current directory.
+
+PATH may be absolute, start with ~, or start with ./ or ../ to be relative to
+the current directory; other relative paths are taken relative to your home
+directory, as in the config file.
+
+The session is named after the directory, as the picker would name it. When
+a session of that name already runs for another directory, a number is
+appended. A session already running for the directory is switched to
+instead of being created again. A --name must not start with '.' or contain
+'.' or ':', which tmux does not allow in session names.
+
+The layout comes from --template, the directory's .muxly file, its configured
+template or the default template, in that order.
+
+Examples:
+  muxly open                       # Open the current directory
+  muxly open ~/Dev/api
+  muxly open . --name scratch --template minimal
+  muxly open ~/notes --detached    # Create it in the background`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeDirs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arg := "."
+		if len(args) == 1 {
+			arg = args[0]
+		}
+		dir, err := openPath(arg)
+		if err != nil {
+			return err
+		}
+
+		var tmpl *models.SessionTemplate
+		if openTemplate != "" {
+			found, ok := config.FindTemplateByName(&cfg, openTemplate)
+			if !ok {
+				return fmt.Errorf("template %q not found", openTemplate)
+			}
+			tmpl = &found
+		}
+
+		entry := openEntry(dir)
+		name, err := openSessionName(dir, openName, entry.SessionName)
+		if err != nil {
+			return err
+		}
+
+		sess, err := sessionForEntry(name, entry, tmpl)
+		if errors.Is(err, errAborted) {
+			fmt.Println("Aborting. No changes made.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if openDetached {
+			return startDetached(sess)
+		}
+
+		if err := tmux.CreateAndSwitchSession(tmuxDriver, &cfg, sess); err != nil {
+			if errors.Is(err, tmux.ErrGracefulExit) {
+				return nil
+			}
+			return fmt.Errorf("Failed to switch session: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().StringVarP(&openName, "name", "n", "", "Session name (default: derived from the directory)")
+	openCmd.Flags().StringVarP(&openTemplate, "template", "t", "", "Template to use instead of the .muxly file or configured template")
+	openCmd.Flags().BoolVarP(&openDetached, "detached", "d", false, "Create the session in the background and print its name instead of switching to it")
+	openCmd.RegisterFlagCompletionFunc("template", completeTemplateNames)
+}
+
+// completeDirs completes a single directory argument.
+func completeDirs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// openPath resolves arg to an existing directory. Paths starting with ./ or
+// ../ are relative to the working directory; everything else goes through
+// utility.ResolvePath.
+func openPath(arg string) (string, error) {
+	var (
+		dir string
+		err error
+	)
+	if arg == "." || arg == ".." || strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") {
+		dir, err = filepath.Abs(arg)
+	} else {
+		dir, err = utility.ResolvePath(arg)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", arg, err)
+	}
+	dir = filepath.Clean(dir)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot open %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// openEntry returns the configured entry for dir, so it keeps the session
+// name and template it has in the picker, or a bare entry for directories
+// outside scan_dirs and entry_dirs.
+func openEntry(dir string) models.DirEntry {
+	for _, entry := range newBuilder().DirEntries(0) {
+		if entry.Path == dir {
+			return entry
+		}
+	}
+	return models.DirEntry{Path: dir}
+}
+
+// openSessionName returns the name of the session to open for dir. An
+// explicit name is used as is, but must be a valid session name and must not
+// belong to a session running for another directory. Otherwise the name is pickerName or the sanitized
+// directory name, numbered "-2", "-3"... while a session of that name runs
+// for another directory.
+func openSessionName(dir, name, pickerName string) (string, error) {
+	runsElsewhere := func(name string) bool {
+		if !tmuxDriver.HasSession(name) {
+			return false
+		}
+		path, err := tmuxDriver.SessionPath(name)
+		return err != nil || filepath.Clean(path) != dir
+	}
+
+	if name != "" {
+		if err := validateSessionName(name); err != nil {
+			return "", fmt.Errorf("invalid --name: %w", err)
+		}
+		if runsElsewhere(name) {
+			return "", fmt.Errorf("session %q already runs for another directory", name)
+		}
+		return name, nil
+	}
+
+	base := pickerName
+	if base == "" {
+		base, _ = selector.SanitizeSessionName(filepath.Base(dir))
+	}
+	if base == "" {
+		return "", fmt.Errorf("cannot derive a session name from %s (use --name)", dir)
+	}
+
+	candidate := base
+	for n := 2; runsElsewhere(candidate); n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return candidate, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestOpenSessionName(t *testing.T) {
+	driver := useFakeTmux(t, "")
+	driver.Sessions = []tmux.FakeSession{
+		{Name: "api", Path: "/work/api"},
+		{Name: "api-2", Path: "/other/api"},
+		{Name: "web", Path: "/code/web"},
+	}
+
+	tests := []struct {
+		name       string
+		dir        string
+		flagName   string
+		pickerName string
+		want       string
+		wantErr    bool
+	}{
+		{name: "directory name", dir: "/code/docs", want: "docs"},
+		{name: "sanitized", dir: "/code/.config.d", want: "config_d"},
+		{name: "picker name", dir: "/code/docs", pickerName: "dev-docs", want: "dev-docs"},
+		{name: "session for the same directory is reused", dir: "/code/web", want: "web"},
+		{name: "numbered past sessions for other directories", dir: "/code/api", want: "api-3"},
+		{name: "explicit name", dir: "/code/api", flagName: "scratch", want: "scratch"},
+		{name: "explicit name of the same directory", dir: "/code/web", flagName: "web", want: "web"},
+		{name: "explicit name taken", dir: "/code/api", flagName: "web", wantErr: true},
+		{name: "explicit name with a dot", dir: "/code/api", flagName: "foo.bar", wantErr: true},
+		{name: "explicit name with a colon", dir: "/code/api", flagName: "foo:bar", wantErr: true},
+		{name: "explicit name starting with a dot", dir: "/code/api", flagName: ".foo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := openSessionName(tt.dir, tt.flagName, tt.pickerName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openSessionName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("openSessionName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.Mkdir("sub", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("file", nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{arg: ".", want: dir},
+		{arg: "./sub", want: filepath.Join(dir, "sub")},
+		{arg: "sub", wantErr: true}, // relative to home, which has no sub
+		{arg: filepath.Join(dir, "sub") + "/", want: filepath.Join(dir, "sub")},
+		{arg: "./file", wantErr: true},
+		{arg: "./missing", wantErr: true},
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := openPath(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("openPath(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("openPath(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenCommandDetached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	driver := useFakeTmux(t, "")
+	cfg.Templates = []models.SessionTemplate{
+		{Name: "default", Default: true, Windows: []models.Window{{Name: "shell"}}},
+		{Name: "go", Windows: []models.Window{{Name: "editor"}, {Name: "test"}}},
+	}
+
+	dir := filepath.Join(t.TempDir(), "my.project")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	openDetached, openTemplate = true, "go"
+	t.Cleanup(func() { openDetached, openTemplate = false, "" })
+	if err := openCmd.RunE(openCmd, []string{dir}); err != nil {
+		t.Fatalf("open error = %v", err)
+	}
+
+	got, ok := driver.Session("my_project")
+	if !ok {
+		t.Fatalf("no session my_project in %v", tmux.GetTmuxSessionNames(driver))
+	}
+	if got.Path != dir || len(got.Windows) != 2 || got.Options[tmux.TemplateOption] != "go" {
+		t.Errorf("session = %+v, want the go template in %s", got, dir)
+	}
+	if len(driver.Switches) != 0 {
+		t.Errorf("Switches = %v, want none for --detached", driver.Switches)
+	}
+}
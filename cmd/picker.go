@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/fzf"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/picker"
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// newPicker returns the picker selected by settings.picker.
+func newPicker() picker.Picker {
+	return picker.New(cfg.Settings.Picker)
+}
+
+// fzfOptions returns the fzf options from settings.fzf, with the options in
+// MUXLY_FZF_OPTS appended so they take precedence.
+func fzfOptions() fzf.Options {
+	s := cfg.Settings.Fzf
+	opts := fzf.Options{
+		Prompt: s.Prompt,
+		Height: s.Height,
+		Layout: s.Layout,
+		Border: s.Border,
+		Color:  s.Color,
+		Tmux:   s.Tmux,
+	}
+
+	// settings.fzf.args is checked by config validation.
+	opts.Args, _ = fzf.SplitArgs(s.Args)
+
+	if env := os.Getenv(constants.EnvFzfOpts); env != "" {
+		args, err := fzf.SplitArgs(env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", constants.EnvFzfOpts, err)
+		} else {
+			opts.Args = append(opts.Args, args...)
+		}
+	}
+
+	return opts
+}
+
+// pickerOptions returns the fzf options for a picker whose previews are
+// rendered by the hidden preview command of this executable, passing the
+// config file and args along. Previews are disabled if the executable cannot
+// be located.
+func pickerOptions(previewArgs ...string) fzf.Options {
+	opts := fzfOptions()
+
+	exe, err := os.Executable()
+	if err != nil {
+		return opts
+	}
+
+	parts := []string{utility.ShellQuote(exe)}
+	if cfgFilePath != "" {
+		if abs, err := filepath.Abs(cfgFilePath); err == nil {
+			parts = append(parts, "--config", utility.ShellQuote(abs))
+		}
+	}
+	for _, arg := range tmuxSocketArgs() {
+		parts = append(parts, utility.ShellQuote(arg))
+	}
+	parts = append(parts, "preview")
+	for _, arg := range previewArgs {
+		parts = append(parts, utility.ShellQuote(arg))
+	}
+	parts = append(parts, "--", "{}")
+
+	opts.Preview = strings.Join(parts, " ")
+	return opts
+}
+
+// entryPickerOptions returns the fzf options for pickers listing entries.
+// Each entry's directory is written to a temporary file the preview command
+// looks labels up in, so previews do not rebuild the entries on every cursor
+// move. cleanup removes the file once the picker has closed.
+func entryPickerOptions(entries map[string]models.DirEntry) (opts fzf.Options, cleanup func()) {
+	paths := make(map[string]string, len(entries))
+	for label, entry := range entries {
+		if entry.Path != "" {
+			paths[label] = entry.Path
+		}
+	}
+
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return pickerOptions(), func() {}
+	}
+	f, err := os.CreateTemp("", "muxly-entries-*.json")
+	if err != nil {
+		return pickerOptions(), func() {}
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	cleanup = func() { os.Remove(f.Name()) }
+	if err != nil {
+		cleanup()
+		return pickerOptions(), func() {}
+	}
+
+	return pickerOptions("--entries", f.Name()), cleanup
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+func TestEntryPickerOptionsWritesEntries(t *testing.T) {
+	useFakeTmux(t, "")
+
+	opts, cleanup := entryPickerOptions(map[string]models.DirEntry{
+		"dev/api":        {Path: "/code/api", SessionName: "api"},
+		"[TMUX] scratch": {SessionName: "scratch"},
+	})
+	t.Cleanup(cleanup)
+
+	m := regexp.MustCompile(`'--entries' '([^']+)'`).FindStringSubmatch(opts.Preview)
+	if m == nil {
+		t.Fatalf("Preview = %q, want an --entries file", opts.Preview)
+	}
+	data, err := os.ReadFile(m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths map[string]string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths["dev/api"] != "/code/api" {
+		t.Errorf("entries file = %v, want only dev/api", paths)
+	}
+
+	cleanup()
+	if _, err := os.Stat(m[1]); !os.IsNotExist(err) {
+		t.Errorf("entries file still exists after cleanup: %v", err)
+	}
+}
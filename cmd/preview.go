@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/preview"
+	"github.com/Pairadux/muxly/internal/utility"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	previewSession bool
+	previewEntries string
+)
+
+// previewCmd renders the fzf preview pane. It is not meant to be run by hand.
+var previewCmd = &cobra.Command{
+	Use:    "preview ENTRY",
+	Short:  "Render the picker preview for an entry",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entry := args[0]
+
+		var err error
+		if name, isSession := strings.CutPrefix(entry, cfg.Settings.TmuxSessionPrefix); previewSession || isSession {
+			if previewSession {
+				name = entry
+			}
+			err = preview.Session(os.Stdout, tmuxDriver, name)
+		} else {
+			err = previewDirectory(entry)
+		}
+
+		// The output ends up in the fzf preview pane, where a cobra error and
+		// usage text would only get in the way.
+		if err != nil {
+			fmt.Printf("No preview available: %v\n", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+	previewCmd.Flags().BoolVar(&previewSession, "session", false, "Treat ENTRY as a tmux session name")
+	previewCmd.Flags().StringVar(&previewEntries, "entries", "", "JSON file mapping picker labels to directories")
+}
+
+// previewDirectory resolves a picker label to its directory through the
+// --entries file written by the picker and previews it. Labels that are not
+// in it are tried as paths.
+func previewDirectory(label string) error {
+	if previewEntries != "" {
+		data, err := os.ReadFile(previewEntries)
+		if err != nil {
+			return err
+		}
+		var paths map[string]string
+		if err := json.Unmarshal(data, &paths); err != nil {
+			return fmt.Errorf("reading %s: %w", previewEntries, err)
+		}
+		if path, ok := paths[label]; ok {
+			return preview.Directory(os.Stdout, path)
+		}
+	}
+
+	path, err := utility.ResolvePath(label)
+	if err != nil {
+		return err
+	}
+	return preview.Directory(os.Stdout, path)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Pairadux/muxly/internal/forms"
+	"github.com/Pairadux/muxly/internal/selector"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// renameCmd renames a running session
+var renameCmd = &cobra.Command{
+	Use:   "rename [OLD] [NEW]",
+	Short: "Rename a running session",
+	Long: `Rename a running tmux session, e.g. to tell apart two projects that are
+both called api.
+
+Without OLD, a picker of running sessions is shown. Without NEW, you are asked
+for the new name. NEW must not start with '.' or contain '.' or ':', which
+tmux does not allow in session names, and must not be the name of another
+running session.
+
+The session keeps its windows, its tmux options (including the template it
+was created from) and its place in recent and frecency order.
+
+Examples:
+  muxly rename                     # Pick a session, then type its new name
+  muxly rename api
+  muxly rename api api-v1`,
+	Args:              cobra.MaximumNArgs(2),
+	ValidArgsFunction: completeSessionNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !tmuxDriver.ServerRunning() {
+			return fmt.Errorf("no tmux server running")
+		}
+
+		var oldName, newName string
+		if len(args) > 0 {
+			oldName = args[0]
+		}
+		if len(args) > 1 {
+			newName = args[1]
+		}
+
+		if oldName == "" {
+			result, err := newPicker().Select(tmux.GetTmuxSessionNames(tmuxDriver), pickerOptions("--session"))
+			if err != nil {
+				if err.Error() == "user cancelled" {
+					return nil
+				}
+				return fmt.Errorf("selecting with fzf failed: %w", err)
+			}
+			if oldName = result.Selection; oldName == "" {
+				return nil
+			}
+		}
+		if !tmuxDriver.HasSession(oldName) {
+			return fmt.Errorf("session %q not found", oldName)
+		}
+
+		if newName == "" {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return fmt.Errorf("NEW is required when not running in a terminal")
+			}
+			newName = oldName
+			if err := renameForm(oldName, &newName).Run(); err != nil {
+				if errors.Is(err, huh.ErrUserAborted) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		renamed, err := renameSession(oldName, newName)
+		if err != nil {
+			return err
+		}
+		if renamed {
+			fmt.Printf("Renamed session %s to %s\n", oldName, newName)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+// renameForm asks for the new name of the session old.
+func renameForm(old string, newName *string) *huh.Form {
+	return forms.InputForm("Rename session", fmt.Sprintf("New name for %q", old), newName, func(name string) error {
+		return validateNewSessionName(old, name)
+	})
+}
+
+// renameSession renames the session old to name after validating name. It
+// reports false when name is the current name and nothing was done.
+func renameSession(old, name string) (bool, error) {
+	if err := validateNewSessionName(old, name); err != nil {
+		return false, err
+	}
+	if name == old {
+		return false, nil
+	}
+	if err := tmux.RenameSession(tmuxDriver, old, name); err != nil {
+		return false, fmt.Errorf("failed to rename session: %w", err)
+	}
+	return true, nil
+}
+
+// validateNewSessionName checks that name can replace the session old: it
+// must be a valid session name and must not be taken by another running
+// session.
+func validateNewSessionName(old, name string) error {
+	if err := validateSessionName(name); err != nil {
+		return err
+	}
+	if name != old && tmux.GetTmuxSessionSet(tmuxDriver)[name] {
+		return fmt.Errorf("session %q already exists", name)
+	}
+	return nil
+}
+
+// validateSessionName checks that name survives
+// selector.SanitizeSessionName unchanged, since tmux would otherwise create
+// the session under a different name.
+func validateSessionName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if sanitized, dots := selector.SanitizeSessionName(name); sanitized != name || dots > 0 {
+		if sanitized == "" {
+			return fmt.Errorf("name cannot start with '.' or contain '.' or ':'")
+		}
+		return fmt.Errorf("name cannot start with '.' or contain '.' or ':' (try %q)", sanitized)
+	}
+	return nil
+}
+
+// completeSessionNames completes the first argument with running sessions.
+func completeSessionNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return tmux.GetTmuxSessionNames(tmuxDriver), cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestValidateNewSessionName(t *testing.T) {
+	useFakeTmux(t, "api", "api", "web")
+
+	tests := []struct {
+		name    string
+		newName string
+		wantErr bool
+	}{
+		{name: "new name", newName: "api-v1"},
+		{name: "unchanged name", newName: "api"},
+		{name: "empty", newName: "", wantErr: true},
+		{name: "dot", newName: "api.v1", wantErr: true},
+		{name: "colon", newName: "api:1", wantErr: true},
+		{name: "leading dot", newName: ".api", wantErr: true},
+		{name: "only dots", newName: "..", wantErr: true},
+		{name: "running session", newName: "web", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNewSessionName("api", tt.newName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateNewSessionName(%q) error = %v, wantErr %v", tt.newName, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantErr      bool
+		wantSessions []string
+	}{
+		{name: "renames", args: []string{"api", "api-v1"}, wantSessions: []string{"main", "api-v1", "web"}},
+		{name: "same name does nothing", args: []string{"api", "api"}, wantSessions: []string{"main", "api", "web"}},
+		{name: "missing session", args: []string{"gone", "new"}, wantErr: true, wantSessions: []string{"main", "api", "web"}},
+		{name: "collision", args: []string{"api", "web"}, wantErr: true, wantSessions: []string{"main", "api", "web"}},
+		{name: "invalid name", args: []string{"api", "api.v1"}, wantErr: true, wantSessions: []string{"main", "api", "web"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := useFakeTmux(t, "main", "main", "api", "web")
+
+			err := renameCmd.RunE(renameCmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rename %v error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+			if got := tmux.GetTmuxSessionNames(driver); !slices.Equal(got, tt.wantSessions) {
+				t.Errorf("sessions = %v, want %v", got, tt.wantSessions)
+			}
+		})
+	}
+}
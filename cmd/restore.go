@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/snapshot"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreAll        bool
+	restoreNoCommands bool
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [NAME]",
+	Short: "Rebuild a session from a saved snapshot",
+	Long: `Rebuild a session from a saved snapshot
+
+Recreates the windows and panes recorded by 'muxly save', each in its saved
+working directory and pane layout, and switches to the session. Commands that
+were running in the panes (other than shells) are started again with their
+arguments unless --no-commands is given.
+
+Without NAME, a snapshot is picked from the saved ones. With --all, every
+snapshot whose session is not running is restored in the background.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if restoreAll {
+			return restoreAllSessions()
+		}
+
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		} else {
+			names, err := snapshot.List()
+			if err != nil {
+				return fmt.Errorf("failed to list snapshots: %w", err)
+			}
+			if len(names) == 0 {
+				fmt.Println("No saved sessions. Use 'muxly save' to create one.")
+				return nil
+			}
+
+			result, err := newPicker().Select(names, fzfOptions())
+			if err != nil {
+				if err.Error() == constants.UserCancelledMsg {
+					return nil
+				}
+				return fmt.Errorf("selecting with fzf failed: %w", err)
+			}
+			if name = result.Selection; name == "" {
+				return nil
+			}
+		}
+
+		snap, err := snapshot.Load(name)
+		if err != nil {
+			return err
+		}
+		if tmuxDriver.HasSession(snap.Name) {
+			return fmt.Errorf("session %q is already running; kill it first to restore it", snap.Name)
+		}
+
+		if err := tmux.CreateAndSwitchSession(tmuxDriver, &cfg, snap.Session(!restoreNoCommands)); err != nil {
+			if errors.Is(err, tmux.ErrGracefulExit) {
+				return nil
+			}
+			return fmt.Errorf("failed to restore session %q: %w", snap.Name, err)
+		}
+
+		return nil
+	},
+}
+
+// restoreAllSessions recreates every saved session that is not running,
+// without switching to any of them. A failed session does not stop the
+// others from being restored.
+func restoreAllSessions() error {
+	names, err := snapshot.List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	failed := 0
+	for _, name := range names {
+		snap, err := snapshot.Load(name)
+		if err != nil {
+			fmt.Printf("Skipped %s: %v\n", name, err)
+			failed++
+			continue
+		}
+		if tmuxDriver.HasSession(snap.Name) {
+			fmt.Printf("Skipped %s: already running\n", snap.Name)
+			continue
+		}
+
+		if err := tmux.CreateDetachedSession(tmuxDriver, &cfg, snap.Session(!restoreNoCommands)); err != nil {
+			fmt.Printf("Failed to restore %s: %v\n", snap.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Restored session %s\n", snap.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sessions could not be restored", failed, len(names))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().BoolVarP(&restoreAll, "all", "a", false, "Restore every saved session that is not running, without switching")
+	restoreCmd.Flags().BoolVar(&restoreNoCommands, "no-commands", false, "Only rebuild windows and panes; do not restart the programs that were running")
+}
@@ -5,17 +5,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"slices"
 	"strings"
 
 	"github.com/Pairadux/muxly/internal/checks"
 	"github.com/Pairadux/muxly/internal/config"
 	"github.com/Pairadux/muxly/internal/constants"
-	"github.com/Pairadux/muxly/internal/fzf"
+	"github.com/Pairadux/muxly/internal/history"
 	"github.com/Pairadux/muxly/internal/models"
 	"github.com/Pairadux/muxly/internal/selector"
-	"github.com/Pairadux/muxly/internal/session"
 	"github.com/Pairadux/muxly/internal/tmux"
+	"github.com/Pairadux/muxly/internal/utility"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,6 +25,16 @@ var (
 	cfgFileFlag string
 	cfgFilePath string
 	verbose     bool
+	noCache     bool
+	multiSelect bool
+	detached    bool
+
+	tmuxSocketName string
+	tmuxSocketPath string
+
+	// tmuxDriver carries out every tmux operation. Tests swap in a
+	// tmux.FakeDriver.
+	tmuxDriver tmux.Driver = tmux.ExecDriver{}
 )
 
 // Version is set at build time via ldflags
@@ -47,7 +56,7 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
-		if err := checks.VerifyExternalUtils(); err != nil {
+		if err := checks.VerifyExternalUtils(cfg.Settings.Picker); err != nil {
 			return err
 		}
 		if err := validateConfig(); err != nil {
@@ -77,77 +86,68 @@ var rootCmd = &cobra.Command{
 			fmt.Printf("templates: %v\n", cfg.Templates)
 			fmt.Printf("tmux_base: %v\n", cfg.Settings.TmuxBase)
 			fmt.Printf("default_depth: %v\n", cfg.Settings.DefaultDepth)
+			fmt.Printf("sort_order: %v\n", cfg.Settings.SortOrder)
+			fmt.Printf("display_mode: %v\n", cfg.Settings.DisplayMode)
 		}
 
 		flagDepth, _ := cmd.Flags().GetInt("depth")
-		builder := selector.NewBuilder(&cfg, verbose)
-		entries, err := builder.BuildEntries(flagDepth)
-		if err != nil {
-			return fmt.Errorf("failed to build directory entries: %w", err)
-		}
 
-		var choiceStr string
+		var (
+			picked  pickResult
+			entries map[string]models.DirEntry
+			err     error
+		)
 		if len(args) == 1 {
-			choiceStr = args[0]
-		}
-		if choiceStr == "" {
-			// Build list of all entry names for fzf selection
-			names := make([]string, 0, len(entries))
-			for name := range entries {
-				names = append(names, name)
+			picked.Choices = args
+			entries, err = newBuilder().BuildEntries(flagDepth)
+			if err != nil {
+				return fmt.Errorf("failed to build directory entries: %w", err)
 			}
-
-			slices.SortFunc(names, func(a, b string) int {
-				isTmuxA := strings.HasPrefix(a, cfg.Settings.TmuxSessionPrefix)
-				isTmuxB := strings.HasPrefix(b, cfg.Settings.TmuxSessionPrefix)
-				if isTmuxA && !isTmuxB {
-					return -1
-				}
-				if !isTmuxA && isTmuxB {
-					return 1
-				}
-				return strings.Compare(strings.ToLower(a), strings.ToLower(b))
-			})
-
-			choiceStr, err = fzf.SelectWithFzf(names)
+		} else {
+			picked, entries, err = pickEntry(flagDepth, multiSelect)
 			if err != nil {
-				if err.Error() == "user cancelled" {
-					return nil
-				}
-				return fmt.Errorf("selecting with fzf failed: %w", err)
+				return err
 			}
-
-			if choiceStr == "" {
+			if len(picked.Choices) == 0 {
 				return nil
 			}
 		}
 
-		sessionName, _ := strings.CutPrefix(choiceStr, cfg.Settings.TmuxSessionPrefix)
-
-		selected, exists := entries[choiceStr]
-		if !exists && len(args) == 0 {
-			return fmt.Errorf("the name must match an existing directory entry: %s", choiceStr)
-		}
-
-		sessionLayout := session.LoadMuxlyFile(selected.Path)
-		if len(sessionLayout.Windows) == 0 && selected.Template != "" {
-			if tmpl, found := config.FindTemplateByName(&cfg, selected.Template); found {
-				sessionLayout = models.SessionLayout{Windows: tmpl.Windows}
+		sessions := make([]models.Session, 0, len(picked.Choices))
+		for _, choiceStr := range picked.Choices {
+			sess, err := resolveSession(choiceStr, entries, picked.Template, len(args) == 1)
+			if errors.Is(err, errAborted) {
+				fmt.Println("Aborting. No changes made.")
+				return nil
+			}
+			if err != nil {
+				return err
 			}
+			sessions = append(sessions, sess)
 		}
-		if len(sessionLayout.Windows) == 0 {
-			if dflt, found := config.DefaultTemplate(&cfg); found {
-				sessionLayout = models.SessionLayout{Windows: dflt.Windows}
+
+		if detached {
+			for _, sess := range sessions {
+				if err := startDetached(sess); err != nil {
+					return err
+				}
 			}
+			return nil
 		}
 
-		sess := models.Session{
-			Name:   sessionName,
-			Path:   selected.Path,
-			Layout: sessionLayout,
+		// With several selections, all but the last are created detached and
+		// the client switches to the last one.
+		last := sessions[len(sessions)-1]
+		for _, sess := range sessions[:len(sessions)-1] {
+			if tmuxDriver.HasSession(sess.Name) {
+				continue
+			}
+			if err := tmux.CreateDetachedSession(tmuxDriver, &cfg, sess); err != nil {
+				return fmt.Errorf("failed to create session %q: %w", sess.Name, err)
+			}
 		}
 
-		if err := tmux.CreateAndSwitchSession(&cfg, sess); err != nil {
+		if err := tmux.CreateAndSwitchSession(tmuxDriver, &cfg, last); err != nil {
 			if errors.Is(err, tmux.ErrGracefulExit) {
 				return nil
 			}
@@ -158,6 +158,108 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// resolveSession turns a picker label into the session to open. The layout
+// comes from tmpl when set, otherwise from the directory's .muxly file, its
+// configured template or the default template, in that order. Labels that
+// are not entries are only accepted when allowUnknown is set, and then name
+// the session directly.
+func resolveSession(choiceStr string, entries map[string]models.DirEntry, tmpl *models.SessionTemplate, allowUnknown bool) (models.Session, error) {
+	sessionName, _ := strings.CutPrefix(choiceStr, cfg.Settings.TmuxSessionPrefix)
+
+	selected, exists := findEntry(choiceStr, sessionName, entries)
+	if !exists && !allowUnknown {
+		return models.Session{}, fmt.Errorf("the name must match an existing directory entry: %s", choiceStr)
+	}
+	if selected.SessionName != "" {
+		sessionName = selected.SessionName
+	}
+
+	return sessionForEntry(sessionName, selected, tmpl)
+}
+
+// findEntry looks choiceStr up as a picker label. Labels only match session
+// names in the "suffix" display mode, so it falls back to the entry whose
+// session name is sessionName and then to the only directory with that
+// sanitized basename, which lets a SESSION argument resolve in every mode.
+func findEntry(choiceStr, sessionName string, entries map[string]models.DirEntry) (models.DirEntry, bool) {
+	if entry, ok := entries[choiceStr]; ok {
+		return entry, true
+	}
+	for _, entry := range entries {
+		if entry.SessionName == sessionName {
+			return entry, true
+		}
+	}
+
+	var (
+		match models.DirEntry
+		count int
+	)
+	for _, entry := range entries {
+		if entry.Source == "" {
+			continue
+		}
+		if base, _ := selector.SanitizeSessionName(filepath.Base(entry.Path)); base == sessionName {
+			match = entry
+			count++
+		}
+	}
+	return match, count == 1
+}
+
+// sessionForEntry returns the session named name for the directory entry,
+// with its layout resolved as described for resolveSession.
+func sessionForEntry(sessionName string, selected models.DirEntry, tmpl *models.SessionTemplate) (models.Session, error) {
+	var sessionLayout models.SessionLayout
+	var templateName string
+	var muxlyHooks *models.Hooks
+	if tmpl != nil {
+		sessionLayout = models.SessionLayout{Windows: tmpl.Windows}
+		templateName = tmpl.Name
+	} else {
+		var err error
+		sessionLayout, err = loadMuxlyFile(selected.Path)
+		if err != nil {
+			return models.Session{}, err
+		}
+		// Copied, since the layout may still be replaced by a template's.
+		loaded := sessionLayout.Hooks
+		muxlyHooks = &loaded
+	}
+	if len(sessionLayout.Windows) == 0 && selected.Template != "" {
+		if tmpl, found := config.FindTemplateByName(&cfg, selected.Template); found {
+			sessionLayout = models.SessionLayout{Windows: tmpl.Windows}
+			templateName = tmpl.Name
+		}
+	}
+	if len(sessionLayout.Windows) == 0 {
+		if dflt, found := config.DefaultTemplate(&cfg); found {
+			sessionLayout = models.SessionLayout{Windows: dflt.Windows}
+			templateName = dflt.Name
+		}
+	}
+
+	return models.Session{
+		Name:       sessionName,
+		Path:       selected.Path,
+		Layout:     sessionLayout,
+		Template:   templateName,
+		MuxlyHooks: muxlyHooks,
+	}, nil
+}
+
+// startDetached creates the session without switching or attaching to it,
+// unless it already runs, and prints its name for scripts.
+func startDetached(sess models.Session) error {
+	if !tmuxDriver.HasSession(sess.Name) {
+		if err := tmux.CreateDetachedSession(tmuxDriver, &cfg, sess); err != nil {
+			return fmt.Errorf("failed to create session %q: %w", sess.Name, err)
+		}
+	}
+	fmt.Println(sess.Name)
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -171,7 +273,13 @@ func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&cfgFileFlag, "config", "", "config file (default $XDG_CONFIG_HOME/muxly/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "Enable verbose output")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Rescan directories instead of using the project index")
+	rootCmd.PersistentFlags().StringVarP(&tmuxSocketName, "socket", "L", "", "Use the tmux server with this socket name (like tmux -L)")
+	rootCmd.PersistentFlags().StringVarP(&tmuxSocketPath, "socket-path", "S", "", "Use the tmux server at this socket path (like tmux -S)")
+	rootCmd.MarkFlagsMutuallyExclusive("socket", "socket-path")
 	rootCmd.Flags().IntP("depth", "d", 0, "Maximum traversal depth")
+	rootCmd.Flags().BoolVarP(&multiSelect, "multi", "m", false, "Select several entries (tab to mark) and open them all")
+	rootCmd.Flags().BoolVar(&detached, "detached", false, "Create the session in the background and print its name instead of switching to it")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -204,17 +312,23 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// Bind environment variables for config overrides
-	// Allows MUXLY_* environment variables to override config file values
+	// Allows MUXLY_* environment variables to override config file values.
+	// Names are explicit since nested keys would otherwise map to MUXLY_SETTINGS.*
 	viper.SetEnvPrefix("MUXLY")
 	viper.BindEnv("settings.editor", "MUXLY_EDITOR", "EDITOR") // Support both MUXLY_EDITOR and standard $EDITOR
-	viper.BindEnv("settings.default_depth")                    // MUXLY_DEFAULT_DEPTH
-	viper.BindEnv("settings.tmux_base")                        // MUXLY_TMUX_BASE
-	viper.BindEnv("settings.tmux_session_prefix")              // MUXLY_TMUX_SESSION_PREFIX
-	viper.BindEnv("settings.always_kill_on_last_session")      // MUXLY_ALWAYS_KILL_ON_LAST_SESSION
+	viper.BindEnv("settings.default_depth", "MUXLY_DEFAULT_DEPTH")
+	viper.BindEnv("settings.tmux_base", "MUXLY_TMUX_BASE")
+	viper.BindEnv("settings.tmux_session_prefix", "MUXLY_TMUX_SESSION_PREFIX")
+	viper.BindEnv("settings.always_kill_on_last_session", "MUXLY_ALWAYS_KILL_ON_LAST_SESSION")
+	viper.BindEnv("settings.sort_order", "MUXLY_SORT_ORDER")
+	viper.BindEnv("settings.display_mode", "MUXLY_DISPLAY_MODE")
+	viper.BindEnv("settings.picker", "MUXLY_PICKER")
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+		// A missing --config file is reported like a missing default one,
+		// so 'muxly --config path config init' can create it.
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok && !errors.Is(err, os.ErrNotExist) {
 			fmt.Fprintln(os.Stderr, "Config file is corrupted or unreadable:", err)
 			os.Exit(1)
 		}
@@ -228,6 +342,11 @@ func initConfig() {
 	}
 
 	config.ApplyDefaults(&cfg)
+	applyTmuxSocket()
+	tmuxDriver = tmux.ExecDriver{
+		SocketName: cfg.Settings.TmuxSocketName,
+		SocketPath: cfg.Settings.TmuxSocketPath,
+	}
 
 	// Sync cfgFilePath with the actual config file that was loaded
 	// This ensures 'muxly config edit' opens the correct file
@@ -236,6 +355,46 @@ func initConfig() {
 	}
 }
 
+// applyTmuxSocket settles which tmux server muxly talks to. MUXLY_TMUX_SOCKET
+// overrides the config file, and --socket / --socket-path override both. The
+// env var holds a socket path if it contains a slash and a socket name
+// otherwise.
+func applyTmuxSocket() {
+	s := &cfg.Settings
+	if s.TmuxSocketPath != "" {
+		if resolved, err := utility.ResolvePath(s.TmuxSocketPath); err == nil {
+			s.TmuxSocketPath = resolved
+		}
+	}
+
+	if env := os.Getenv(constants.EnvTmuxSocket); env != "" {
+		if strings.ContainsRune(env, '/') {
+			s.TmuxSocketName, s.TmuxSocketPath = "", env
+		} else {
+			s.TmuxSocketName, s.TmuxSocketPath = env, ""
+		}
+	}
+
+	switch {
+	case tmuxSocketPath != "":
+		s.TmuxSocketName, s.TmuxSocketPath = "", tmuxSocketPath
+	case tmuxSocketName != "":
+		s.TmuxSocketName, s.TmuxSocketPath = tmuxSocketName, ""
+	}
+}
+
+// tmuxSocketArgs returns the flags that make another muxly process use the
+// same tmux server as this one.
+func tmuxSocketArgs() []string {
+	switch {
+	case cfg.Settings.TmuxSocketPath != "":
+		return []string{"--socket-path", cfg.Settings.TmuxSocketPath}
+	case cfg.Settings.TmuxSocketName != "":
+		return []string{"--socket", cfg.Settings.TmuxSocketName}
+	}
+	return nil
+}
+
 // bypassesStartupChecks checks if the given command should skip the standard
 // startup validation (external utils and config). Commands like "config" and
 // "doctor" need to run even when the environment isn't fully configured.
@@ -254,9 +413,24 @@ func bypassesStartupChecks(cmd *cobra.Command) bool {
 // It checks for the presence of a config file and validates the config structure.
 // Returns an error with helpful instructions if validation fails.
 func validateConfig() error {
-	if viper.ConfigFileUsed() == "" {
+	if _, err := os.Stat(viper.ConfigFileUsed()); viper.ConfigFileUsed() == "" || errors.Is(err, os.ErrNotExist) {
 		return fmt.Errorf("no config file found\nRun 'muxly config init' to create one, or use --config to specify a path\n")
 	}
 
 	return config.Validate(&cfg)
 }
+
+// sortEntryNames orders picker entries by the configured sort order, loading
+// the history store when the order depends on it.
+func sortEntryNames(names []string, entries map[string]models.DirEntry) {
+	var hist *history.Store
+	if cfg.Settings.SortOrder != config.SortAlphabetical {
+		var err error
+		hist, err = history.LoadDefault()
+		if err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load history: %v\n", err)
+		}
+	}
+
+	selector.SortEntryNames(names, entries, cfg.Settings.TmuxSessionPrefix, cfg.Settings.SortOrder, hist)
+}
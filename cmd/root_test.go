@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+func TestResolveSessionArgument(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	for _, dir := range []string{"Dev/web", "Dev/my.app"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, mode := range config.DisplayModes {
+		t.Run(mode, func(t *testing.T) {
+			useFakeTmux(t, "")
+			cfg.ScanDirs = []models.ScanDir{{Path: filepath.Join(home, "Dev"), Alias: "dev"}}
+			cfg.Settings.DefaultDepth = 1
+			cfg.Settings.DisplayMode = mode
+
+			entries, err := newBuilder().BuildEntries(0)
+			if err != nil {
+				t.Fatalf("BuildEntries() error = %v", err)
+			}
+
+			for arg, want := range map[string]string{"web": "Dev/web", "my_app": "Dev/my.app"} {
+				sess, err := resolveSession(arg, entries, nil, true)
+				if err != nil {
+					t.Fatalf("resolveSession(%q) error = %v", arg, err)
+				}
+				if sess.Name != arg || sess.Path != filepath.Join(home, want) {
+					t.Errorf("resolveSession(%q) = %q at %q, want %q at %q", arg, sess.Name, sess.Path, arg, filepath.Join(home, want))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/snapshot"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+var saveAll bool
+
+// saveCmd represents the save command
+var saveCmd = &cobra.Command{
+	Use:   "save [SESSION]",
+	Short: "Save a snapshot of a session's windows and panes",
+	Long: `Save a snapshot of a session's windows and panes
+
+Records every window's name and pane layout along with each pane's working
+directory and running command with its arguments, so 'muxly restore' can
+rebuild the session after a reboot or kill-server. Snapshots are stored in
+$XDG_STATE_HOME/muxly/snapshots, one per session; saving again replaces it.
+
+Without SESSION, the current session is saved, or one is picked from the
+active sessions when not in tmux.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if saveAll {
+			sessions := tmux.GetTmuxSessionNames(tmuxDriver)
+			if len(sessions) == 0 {
+				fmt.Println("No tmux sessions to save.")
+				return nil
+			}
+			for _, name := range sessions {
+				if err := saveSession(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		name, err := sessionArgOrPick(args)
+		if err != nil || name == "" {
+			return err
+		}
+
+		return saveSession(name)
+	},
+}
+
+// sessionArgOrPick returns the session named in args, else the current
+// session, else one picked from the active sessions. It returns "" when
+// there is nothing to pick from or the picker is cancelled.
+func sessionArgOrPick(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+	if current := tmuxDriver.CurrentSession(); current != "" {
+		return current, nil
+	}
+
+	sessions := tmux.GetTmuxSessionNames(tmuxDriver)
+	if len(sessions) == 0 {
+		fmt.Println("No active tmux sessions.")
+		return "", nil
+	}
+
+	result, err := newPicker().Select(sessions, pickerOptions("--session"))
+	if err != nil {
+		if err.Error() == constants.UserCancelledMsg {
+			return "", nil
+		}
+		return "", fmt.Errorf("selecting with fzf failed: %w", err)
+	}
+	return result.Selection, nil
+}
+
+// saveSession snapshots the named session and reports where it was saved.
+func saveSession(name string) error {
+	snap, err := snapshot.Capture(tmuxDriver, name)
+	if err != nil {
+		return fmt.Errorf("failed to capture session %q: %w", name, err)
+	}
+
+	path, err := snapshot.Save(snap)
+	if err != nil {
+		return fmt.Errorf("failed to save session %q: %w", name, err)
+	}
+
+	fmt.Printf("Saved session %s (%d windows) to %s\n", name, len(snap.Windows), path)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(saveCmd)
+	saveCmd.Flags().BoolVarP(&saveAll, "all", "a", false, "Save every active session")
+}
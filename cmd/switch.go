@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/Pairadux/muxly/internal/fzf"
 	"github.com/Pairadux/muxly/internal/tmux"
 
 	"github.com/spf13/cobra"
@@ -22,7 +21,7 @@ Displays a fzf picker list of active sessions.
 If no other sessions found, exit.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		currentSession := tmux.GetCurrentTmuxSession()
+		currentSession := tmuxDriver.CurrentSession()
 
 		if currentSession == "" {
 			return fmt.Errorf("Not in Tmux, use 'muxly' to get started.")
@@ -33,9 +32,13 @@ If no other sessions found, exit.`,
 			choiceStr = args[0]
 		}
 		if choiceStr == "" {
-			sessions := tmux.GetSessionsExceptCurrent(currentSession)
+			sessions := tmux.GetSessionsExceptCurrent(tmuxDriver, currentSession)
 
 			if len(sessions) == 0 {
+				if skipEnter {
+					fmt.Println("No other sessions available.")
+					return nil
+				}
 				fmt.Println("No other sessions available. Press 'Enter' to continue...")
 				_, err := fmt.Scanln()
 				if err != nil {
@@ -44,8 +47,7 @@ If no other sessions found, exit.`,
 				return nil
 			}
 
-			var err error
-			choiceStr, err = fzf.SelectWithFzf(sessions)
+			result, err := newPicker().Select(sessions, pickerOptions("--session"))
 			if err != nil {
 				if err.Error() == "user cancelled" {
 					return nil
@@ -53,12 +55,13 @@ If no other sessions found, exit.`,
 				return fmt.Errorf("selecting with fzf failed: %w", err)
 			}
 
+			choiceStr = result.Selection
 			if choiceStr == "" {
 				return nil
 			}
 		}
 		sessionName := choiceStr
-		if err := tmux.SwitchToExistingSession(&cfg, sessionName); err != nil {
+		if err := tmux.SwitchToExistingSession(tmuxDriver, &cfg, sessionName); err != nil {
 			if errors.Is(err, tmux.ErrGracefulExit) {
 				return nil
 			}
@@ -71,5 +74,5 @@ If no other sessions found, exit.`,
 
 func init() {
 	rootCmd.AddCommand(switchCmd)
-	rootCmd.Flags().BoolVarP(&skipEnter, "skip-enter", "s", false, "Skip the 'press Enter' confirmation prompt when no other sessions found")
+	switchCmd.Flags().BoolVarP(&skipEnter, "skip-enter", "s", false, "Skip the 'press Enter' confirmation prompt when no other sessions found")
 }
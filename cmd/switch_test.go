@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestSwitchSkipEnter(t *testing.T) {
+	prevStdin := os.Stdin
+	t.Cleanup(func() {
+		os.Stdin = prevStdin
+		skipEnter = false
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantRead bool
+	}{
+		{name: "waits for Enter", wantRead: true},
+		{name: "-s skips the prompt", args: []string{"-s"}},
+		{name: "--skip-enter skips the prompt", args: []string{"--skip-enter"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := useFakeTmux(t, "api", "api")
+			skipEnter = false
+			if err := switchCmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			r, w, err := os.Pipe()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			w.WriteString("\n")
+			w.Close()
+			os.Stdin = r
+
+			if err := switchCmd.RunE(switchCmd, nil); err != nil {
+				t.Fatalf("switch error = %v", err)
+			}
+			rest, _ := io.ReadAll(r)
+			if read := len(rest) == 0; read != tt.wantRead {
+				t.Errorf("read Enter from stdin = %v, want %v", read, tt.wantRead)
+			}
+			if len(driver.Switches) != 0 {
+				t.Errorf("Switches = %v, want none", driver.Switches)
+			}
+		})
+	}
+}
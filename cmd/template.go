@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage session templates",
+	Long: `Manage the session templates in the configuration file.
+
+Subcommands:
+  list         - List templates, marking the default
+  show         - Print a template as YAML
+  add          - Build a new template interactively
+  remove       - Remove a template
+  rename       - Rename a template and everything using it
+  set-default  - Make a template the default
+  capture      - Save a running session's windows as a template
+
+Examples:
+  muxly template list
+  muxly template show dev
+  muxly template rename dev go-service
+  muxly template capture                 # Save the current session as a template`,
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+}
+
+// templateIndex returns the position of the named template in cfg.Templates.
+func templateIndex(name string) (int, error) {
+	i := slices.IndexFunc(cfg.Templates, func(t models.SessionTemplate) bool { return t.Name == name })
+	if i < 0 {
+		return -1, fmt.Errorf("template %q not found", name)
+	}
+	return i, nil
+}
+
+// templateUsers returns the scan_dirs, entry_dirs and workspace members that
+// use the named template.
+func templateUsers(name string) []string {
+	var paths []string
+	for _, sd := range cfg.ScanDirs {
+		if sd.Template == name {
+			paths = append(paths, sd.Path)
+		}
+	}
+	for _, ed := range cfg.EntryDirs {
+		if ed.Template == name {
+			paths = append(paths, ed.Path)
+		}
+	}
+	for _, ws := range cfg.Workspaces {
+		for _, member := range ws.Members {
+			if member.Template == name {
+				paths = append(paths, fmt.Sprintf("%s in workspace %s", member.Entry, ws.Name))
+			}
+		}
+	}
+	return paths
+}
+
+// writeTemplates writes the templates of updated back to the config file,
+// along with the scan_dirs, entry_dirs and workspaces that refer to
+// templates by name, refusing changes that would make the config invalid.
+// The file is rewritten from its own contents rather than the global viper,
+// which also holds MUXLY_* and EDITOR overrides that must not be persisted.
+func writeTemplates(updated models.Config) error {
+	if err := config.Validate(&updated); err != nil {
+		return fmt.Errorf("refusing to write an invalid config: %w", err)
+	}
+
+	file := viper.New()
+	file.SetConfigFile(viper.ConfigFileUsed())
+	if err := file.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	file.Set("templates", updated.Templates)
+	file.Set("scan_dirs", updated.ScanDirs)
+	file.Set("entry_dirs", updated.EntryDirs)
+	if len(updated.Workspaces) > 0 {
+		file.Set("workspaces", updated.Workspaces)
+	}
+	if err := file.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	cfg = updated
+	return nil
+}
+
+// withTemplates returns a copy of cfg using templates.
+func withTemplates(templates []models.SessionTemplate) models.Config {
+	updated := cfg
+	updated.Templates = templates
+	return updated
+}
+
+// completeTemplateNames completes the first argument with template names.
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(cfg.Templates))
+	for _, tmpl := range cfg.Templates {
+		names = append(names, tmpl.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/forms"
+	"github.com/Pairadux/muxly/internal/models"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// templateAddCmd builds a new template interactively
+var templateAddCmd = &cobra.Command{
+	Use:   "add [NAME]",
+	Short: "Build a new template interactively",
+	Long: `Build a new template step by step and add it to the configuration file.
+
+Asks for the template's name and settings, then for each window its name,
+command and directory, and any extra panes to split off it. Layouts and pane
+sizes can be fine-tuned afterwards with 'muxly config edit'.
+
+To turn a session you already arranged into a template, use
+'muxly template capture' instead.
+
+Examples:
+  muxly template add
+  muxly template add rust`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("template add is interactive; use 'muxly template capture' or 'muxly config edit' instead")
+		}
+
+		var tmpl models.SessionTemplate
+		if len(args) == 1 {
+			if err := validateNewTemplateName(args[0]); err != nil {
+				return err
+			}
+			tmpl.Name = args[0]
+		}
+
+		form := forms.TemplateForm(&tmpl.Name, &tmpl.Label, &tmpl.Path, &tmpl.Default, validateNewTemplateName)
+		if err := form.Run(); err != nil {
+			return fmt.Errorf("template form failed: %w", err)
+		}
+		tmpl.Name = strings.TrimSpace(tmpl.Name)
+		tmpl.Label = strings.TrimSpace(tmpl.Label)
+		tmpl.Path = strings.TrimSpace(tmpl.Path)
+
+		for {
+			window, err := runWindowForms(len(tmpl.Windows) + 1)
+			if err != nil {
+				return err
+			}
+			tmpl.Windows = append(tmpl.Windows, window)
+
+			var another bool
+			confirm := forms.ConfirmationForm("Add another window?", fmt.Sprintf("%d window(s) so far", len(tmpl.Windows)), &another)
+			if err := confirm.Run(); err != nil {
+				return fmt.Errorf("failed to run confirmation form: %w", err)
+			}
+			if !another {
+				break
+			}
+		}
+
+		templates := slices.Clone(cfg.Templates)
+		if tmpl.Default {
+			for i := range templates {
+				templates[i].Default = false
+			}
+		}
+		if err := writeTemplates(withTemplates(append(templates, tmpl))); err != nil {
+			return err
+		}
+
+		fmt.Printf("Added template %q with %d window(s)\n", tmpl.Name, len(tmpl.Windows))
+		return nil
+	},
+}
+
+// runWindowForms asks for the nth window of a new template and the panes to
+// split off it.
+func runWindowForms(n int) (models.Window, error) {
+	var window models.Window
+	if err := forms.WindowForm(fmt.Sprintf("Window %d", n), &window).Run(); err != nil {
+		return models.Window{}, fmt.Errorf("window form failed: %w", err)
+	}
+	window.Name = strings.TrimSpace(window.Name)
+	window.Cmd = strings.TrimSpace(window.Cmd)
+	window.Path = strings.TrimSpace(window.Path)
+
+	for {
+		var split bool
+		confirm := forms.ConfirmationForm(fmt.Sprintf("Split another pane off %q?", window.Name), "", &split)
+		if err := confirm.Run(); err != nil {
+			return models.Window{}, fmt.Errorf("failed to run confirmation form: %w", err)
+		}
+		if !split {
+			return window, nil
+		}
+
+		pane := models.Pane{Split: "vertical"}
+		title := fmt.Sprintf("Pane %d of %q", len(window.Panes)+2, window.Name)
+		if err := forms.PaneForm(title, &pane).Run(); err != nil {
+			return models.Window{}, fmt.Errorf("pane form failed: %w", err)
+		}
+		pane.Cmd = strings.TrimSpace(pane.Cmd)
+		window.Panes = append(window.Panes, pane)
+	}
+}
+
+// validateNewTemplateName rejects empty names and names already in use.
+func validateNewTemplateName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("a template needs a name")
+	}
+	if _, err := templateIndex(name); err == nil {
+		return fmt.Errorf("template %q already exists", name)
+	}
+	return nil
+}
+
+func init() {
+	templateCmd.AddCommand(templateAddCmd)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/session"
+	"github.com/Pairadux/muxly/internal/snapshot"
+
+	"github.com/spf13/cobra"
+)
+
+// Destinations for template capture --to
+const (
+	captureToConfig    = "config"
+	captureToMuxlyFile = "muxlyfile"
+)
+
+var (
+	captureTo    string
+	captureName  string
+	captureForce bool
+)
+
+// templateCaptureCmd saves a running session's windows as a template
+var templateCaptureCmd = &cobra.Command{
+	Use:   "capture [SESSION]",
+	Short: "Save a running session's windows as a template or .muxly file",
+	Long: `Save a running session's windows as a template or .muxly file.
+
+Reads the session's windows from tmux: their names, pane layouts, the command
+running in each pane with its arguments, and each pane's directory relative
+to the session directory. Without SESSION, the current session is captured,
+or one is picked from the active sessions when not in tmux.
+
+--to config (the default) adds the layout to templates in the config file,
+named after the session unless --name is given. --to muxlyfile writes it to a
+.muxly file in the session directory, which is used the next time that
+directory is opened. Existing templates and .muxly files are only replaced
+with --force.
+
+Examples:
+  muxly template capture                          # Current session into the config
+  muxly template capture api --name go-service    # As template "go-service"
+  muxly template capture --to muxlyfile           # Into the project's .muxly`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !slices.Contains([]string{captureToConfig, captureToMuxlyFile}, captureTo) {
+			return fmt.Errorf("invalid --to %q (use %s or %s)", captureTo, captureToConfig, captureToMuxlyFile)
+		}
+
+		name, err := sessionArgOrPick(args)
+		if err != nil || name == "" {
+			return err
+		}
+
+		snap, err := snapshot.Capture(tmuxDriver, name)
+		if err != nil {
+			return fmt.Errorf("failed to read session %q: %w", name, err)
+		}
+		layout := snap.Layout()
+		if err := config.ValidateWindows(layout.Windows); err != nil {
+			return fmt.Errorf("session %q cannot be captured: %w", name, err)
+		}
+
+		if captureTo == captureToMuxlyFile {
+			return captureToMuxly(snap.Path, layout)
+		}
+
+		tmplName := captureName
+		if tmplName == "" {
+			tmplName = name
+		}
+		return captureToTemplate(tmplName, layout)
+	},
+}
+
+// captureToTemplate adds layout to the config as the template tmplName,
+// replacing a template of that name only with --force.
+func captureToTemplate(tmplName string, layout models.SessionLayout) error {
+	templates := slices.Clone(cfg.Templates)
+	tmpl := models.SessionTemplate{Name: tmplName, Windows: layout.Windows}
+
+	if i := slices.IndexFunc(templates, func(t models.SessionTemplate) bool { return t.Name == tmplName }); i >= 0 {
+		if !captureForce {
+			return fmt.Errorf("template %q already exists (use --force to replace it, or --name to pick another name)", tmplName)
+		}
+		// Replacing keeps the template's place, label and default flag.
+		tmpl.Label, tmpl.Default = templates[i].Label, templates[i].Default
+		templates[i] = tmpl
+	} else {
+		templates = append(templates, tmpl)
+	}
+
+	if err := writeTemplates(withTemplates(templates)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved template %q (%d windows) to %s\n", tmplName, len(layout.Windows), cfgFilePath)
+	return nil
+}
+
+// captureToMuxly writes layout to the .muxly file in dir, replacing an
+// existing one only with --force.
+func captureToMuxly(dir string, layout models.SessionLayout) error {
+	existing := filepath.Join(dir, session.MuxlyFileName)
+	if _, err := os.Stat(existing); err == nil && !captureForce {
+		return fmt.Errorf("%s already exists (use --force to replace it)", existing)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to access %s: %w", existing, err)
+	}
+
+	path, err := session.WriteMuxlyFile(dir, layout)
+	if err != nil {
+		return err
+	}
+	// The commands come from the user's own session, so the file is trusted.
+	if data, err := os.ReadFile(path); err == nil {
+		if err := trustMuxlyFile(path, data); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Wrote %d windows to %s\n", len(layout.Windows), path)
+	return nil
+}
+
+func init() {
+	templateCmd.AddCommand(templateCaptureCmd)
+	templateCaptureCmd.Flags().StringVar(&captureTo, "to", captureToConfig, "Where to save the layout: config or muxlyfile")
+	templateCaptureCmd.Flags().StringVarP(&captureName, "name", "n", "", "Template name (default: the session name; only with --to config)")
+	templateCaptureCmd.Flags().BoolVarP(&captureForce, "force", "f", false, "Replace an existing template or .muxly file")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/session"
+	"github.com/Pairadux/muxly/internal/snapshot"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestCaptureToMuxlyRoundTrip(t *testing.T) {
+	driver := useFakeTmux(t, "")
+	root := t.TempDir()
+	windows := []models.Window{
+		{Name: "editor", Cmd: "nvim"},
+		{Name: "server", Path: "api", Cmd: "go run .", Panes: []models.Pane{{Path: "web"}}, Layout: "even-horizontal"},
+	}
+	driver.Sessions = []tmux.FakeSession{{Name: "proj", Path: root, Windows: windows}}
+
+	snap, err := snapshot.Capture(driver, "proj")
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+	if err := captureToMuxly(root, snap.Layout()); err != nil {
+		t.Fatalf("captureToMuxly() error = %v", err)
+	}
+
+	layout, err := session.LoadMuxlyFile(root)
+	if err != nil {
+		t.Fatalf("LoadMuxlyFile() error = %v", err)
+	}
+	if !slices.EqualFunc(layout.Windows, windows, func(a, b models.Window) bool {
+		return a.Name == b.Name && a.Path == b.Path && a.Cmd == b.Cmd && a.Layout == b.Layout && slices.Equal(a.Panes, b.Panes)
+	}) {
+		t.Errorf("loaded windows = %+v, want %+v", layout.Windows, windows)
+	}
+
+	// A second capture must not overwrite the file without --force.
+	if err := captureToMuxly(root, snap.Layout()); err == nil {
+		t.Error("captureToMuxly() replaced an existing .muxly file without --force")
+	}
+	if _, err := os.Stat(filepath.Join(root, session.MuxlyFileName)); err != nil {
+		t.Errorf(".muxly file missing: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// templateListCmd lists the configured templates
+var templateListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List templates",
+	Long: `List the templates in the configuration file.
+
+The default template, used for directories without a template of their own,
+is marked with *.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		width := 0
+		for _, tmpl := range cfg.Templates {
+			width = max(width, len(tmpl.Name))
+		}
+
+		for _, tmpl := range cfg.Templates {
+			marker := " "
+			if tmpl.Default {
+				marker = "*"
+			}
+			details := fmt.Sprintf("%d window(s)", len(tmpl.Windows))
+			if tmpl.Label != "" {
+				details = tmpl.Label + ", " + details
+			}
+			if tmpl.Path != "" {
+				details += ", in " + tmpl.Path
+			}
+			fmt.Printf("%s %-*s  %s\n", marker, width, tmpl.Name, details)
+		}
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd)
+}
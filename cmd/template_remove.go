@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// templateRemoveCmd removes a template
+var templateRemoveCmd = &cobra.Command{
+	Use:     "remove NAME",
+	Aliases: []string{"rm"},
+	Short:   "Remove a template",
+	Long: `Remove a template from the configuration file.
+
+The default template and templates still used by scan_dirs, entry_dirs or
+workspaces cannot be removed: set another default or change those entries
+first.
+
+Examples:
+  muxly template remove old-layout`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		i, err := templateIndex(name)
+		if err != nil {
+			return err
+		}
+
+		if cfg.Templates[i].Default {
+			return fmt.Errorf("template %q is the default; make another template the default first ('muxly template set-default')", name)
+		}
+		if users := templateUsers(name); len(users) > 0 {
+			return fmt.Errorf("template %q is still used by %s", name, strings.Join(users, ", "))
+		}
+
+		if err := writeTemplates(withTemplates(slices.Delete(slices.Clone(cfg.Templates), i, i+1))); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed template %q\n", name)
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateRemoveCmd)
+}
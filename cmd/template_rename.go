@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// templateRenameCmd renames a template
+var templateRenameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename a template",
+	Long: `Rename a template. scan_dirs, entry_dirs and workspace members using the
+template are updated to the new name, and so are running sessions created
+from it, so their template hooks and 'muxly list --template' keep working.
+
+Examples:
+  muxly template rename dev go-service`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldName, newName := args[0], args[1]
+		i, err := templateIndex(oldName)
+		if err != nil {
+			return err
+		}
+		if newName == oldName {
+			return nil
+		}
+		if _, err := templateIndex(newName); err == nil {
+			return fmt.Errorf("template %q already exists", newName)
+		}
+
+		updated := withTemplates(slices.Clone(cfg.Templates))
+		updated.Templates[i].Name = newName
+		updated.ScanDirs = slices.Clone(cfg.ScanDirs)
+		for j := range updated.ScanDirs {
+			if updated.ScanDirs[j].Template == oldName {
+				updated.ScanDirs[j].Template = newName
+			}
+		}
+		updated.EntryDirs = slices.Clone(cfg.EntryDirs)
+		for j := range updated.EntryDirs {
+			if updated.EntryDirs[j].Template == oldName {
+				updated.EntryDirs[j].Template = newName
+			}
+		}
+
+		updated.Workspaces = slices.Clone(cfg.Workspaces)
+		for j := range updated.Workspaces {
+			members := slices.Clone(updated.Workspaces[j].Members)
+			for k := range members {
+				if members[k].Template == oldName {
+					members[k].Template = newName
+				}
+			}
+			updated.Workspaces[j].Members = members
+		}
+
+		users := templateUsers(oldName)
+		if err := writeTemplates(updated); err != nil {
+			return err
+		}
+
+		sessions := retagSessions(oldName, newName)
+
+		fmt.Printf("Renamed template %q to %q", oldName, newName)
+		if len(users) > 0 {
+			fmt.Printf(" (updated %d entries using it)", len(users))
+		}
+		if sessions > 0 {
+			fmt.Printf(" (updated %d running sessions)", sessions)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// retagSessions points running sessions created from template oldName at
+// newName and returns how many it updated. Failures are only warned about,
+// since the config has already been written.
+func retagSessions(oldName, newName string) int {
+	updated := 0
+	for _, name := range tmux.GetTmuxSessionNames(tmuxDriver) {
+		if tmpl, err := tmuxDriver.SessionOption(name, tmux.TemplateOption); err != nil || tmpl != oldName {
+			continue
+		}
+		if err := tmuxDriver.SetSessionOption(name, tmux.TemplateOption, newName); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: session %s still refers to template %q: %v\n", name, oldName, err)
+			continue
+		}
+		updated++
+	}
+	return updated
+}
+
+func init() {
+	templateCmd.AddCommand(templateRenameCmd)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// templateSetDefaultCmd makes a template the default
+var templateSetDefaultCmd = &cobra.Command{
+	Use:   "set-default NAME",
+	Short: "Make a template the default",
+	Long: `Make a template the default, used for directories without a template of
+their own. The previous default stays as a regular template.
+
+Examples:
+  muxly template set-default dev`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		i, err := templateIndex(name)
+		if err != nil {
+			return err
+		}
+		if cfg.Templates[i].Default {
+			fmt.Printf("Template %q is already the default\n", name)
+			return nil
+		}
+
+		templates := slices.Clone(cfg.Templates)
+		for j := range templates {
+			templates[j].Default = j == i
+		}
+		if err := writeTemplates(withTemplates(templates)); err != nil {
+			return err
+		}
+
+		fmt.Printf("Template %q is now the default\n", name)
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateSetDefaultCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// templateShowCmd prints a template
+var templateShowCmd = &cobra.Command{
+	Use:   "show NAME",
+	Short: "Print a template as YAML",
+	Long: `Print a template as YAML, in the form it takes in the configuration file.
+
+Examples:
+  muxly template show dev`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		i, err := templateIndex(args[0])
+		if err != nil {
+			return err
+		}
+
+		data, err := yaml.Marshal(cfg.Templates[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode template: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	templateCmd.AddCommand(templateShowCmd)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// useTempConfig writes c to a temporary config file and points the commands
+// at it. It returns the file's path.
+func useTempConfig(t *testing.T, c models.Config) string {
+	t.Helper()
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prevCfg := cfg
+	t.Cleanup(func() {
+		cfg = prevCfg
+		viper.Reset()
+	})
+	viper.Reset()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	cfg = c
+
+	return path
+}
+
+func templateTestConfig() models.Config {
+	window := []models.Window{{Name: "main"}}
+	return models.Config{
+		ScanDirs:  []models.ScanDir{{Path: "/dev", Template: "go"}},
+		EntryDirs: []models.EntryDir{{Path: "/notes", Template: "go"}, {Path: "/web"}},
+		Templates: []models.SessionTemplate{
+			{Name: "default", Default: true, Windows: window},
+			{Name: "go", Windows: window},
+			{Name: "spare", Windows: window},
+		},
+		Workspaces: []models.Workspace{{Name: "daily", Members: []models.WorkspaceMember{
+			{Entry: "api", Template: "go"},
+			{Entry: "~/web"},
+		}}},
+	}
+}
+
+func TestTemplateRenameUpdatesDirectories(t *testing.T) {
+	driver := useFakeTmux(t, "", "api", "notes")
+	driver.Sessions[0].Options = map[string]string{tmux.TemplateOption: "go"}
+	driver.Sessions[1].Options = map[string]string{tmux.TemplateOption: "spare"}
+	path := useTempConfig(t, templateTestConfig())
+
+	if err := templateRenameCmd.RunE(templateRenameCmd, []string{"go", "golang"}); err != nil {
+		t.Fatalf("rename error = %v", err)
+	}
+
+	got, err := config.ValidateConfigFile(path)
+	if err != nil {
+		t.Fatalf("config after rename is invalid: %v", err)
+	}
+	if got.Templates[1].Name != "golang" {
+		t.Errorf("templates = %+v, want go renamed to golang", got.Templates)
+	}
+	if got.ScanDirs[0].Template != "golang" || got.EntryDirs[0].Template != "golang" {
+		t.Errorf("directories still use the old name: %+v %+v", got.ScanDirs, got.EntryDirs)
+	}
+	if got.EntryDirs[1].Template != "" {
+		t.Errorf("entry without a template got %q", got.EntryDirs[1].Template)
+	}
+	if members := got.Workspaces[0].Members; members[0].Template != "golang" || members[1].Template != "" {
+		t.Errorf("workspace members = %+v, want api renamed to golang", members)
+	}
+	for name, want := range map[string]string{"api": "golang", "notes": "spare"} {
+		if tmpl, _ := driver.SessionOption(name, tmux.TemplateOption); tmpl != want {
+			t.Errorf("session %s has template %q, want %q", name, tmpl, want)
+		}
+	}
+}
+
+func TestTemplateRenameToExistingName(t *testing.T) {
+	useTempConfig(t, templateTestConfig())
+
+	if err := templateRenameCmd.RunE(templateRenameCmd, []string{"go", "spare"}); err == nil {
+		t.Fatal("rename onto an existing template succeeded")
+	}
+}
+
+func TestTemplateRemove(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		wantErr   bool
+		wantCount int
+	}{
+		{name: "unused template", target: "spare", wantCount: 2},
+		{name: "default template", target: "default", wantErr: true, wantCount: 3},
+		{name: "template in use", target: "go", wantErr: true, wantCount: 3},
+		{name: "unknown template", target: "nope", wantErr: true, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := useTempConfig(t, templateTestConfig())
+
+			err := templateRemoveCmd.RunE(templateRemoveCmd, []string{tt.target})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("remove error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			got, err := config.ValidateConfigFile(path)
+			if err != nil {
+				t.Fatalf("config after remove is invalid: %v", err)
+			}
+			if len(got.Templates) != tt.wantCount {
+				t.Errorf("%d templates after remove, want %d", len(got.Templates), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestTemplateSetDefault(t *testing.T) {
+	path := useTempConfig(t, templateTestConfig())
+
+	if err := templateSetDefaultCmd.RunE(templateSetDefaultCmd, []string{"spare"}); err != nil {
+		t.Fatalf("set-default error = %v", err)
+	}
+
+	got, err := config.ValidateConfigFile(path)
+	if err != nil {
+		t.Fatalf("config after set-default is invalid: %v", err)
+	}
+	if tmpl, _ := config.DefaultTemplate(got); tmpl.Name != "spare" {
+		t.Errorf("default template = %q, want spare", tmpl.Name)
+	}
+}
+
+func TestTemplateWriteKeepsEnvOverridesOut(t *testing.T) {
+	path := useTempConfig(t, templateTestConfig())
+	t.Setenv("MUXLY_SORT_ORDER", "recent")
+	t.Setenv("EDITOR", "ed")
+	viper.BindEnv("settings.sort_order", "MUXLY_SORT_ORDER")
+	viper.BindEnv("settings.editor", "MUXLY_EDITOR", "EDITOR")
+
+	if err := templateSetDefaultCmd.RunE(templateSetDefaultCmd, []string{"spare"}); err != nil {
+		t.Fatalf("set-default error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got models.Config
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Settings.SortOrder == "recent" || got.Settings.Editor == "ed" {
+		t.Errorf("settings after set-default = %+v, want no environment overrides", got.Settings)
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/forms"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/session"
+	"github.com/Pairadux/muxly/internal/trust"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// trustCmd allows the commands in a .muxly file to run
+var trustCmd = &cobra.Command{
+	Use:   "trust [PATH]",
+	Short: "Allow the commands in a .muxly file to run",
+	Long: `Allow the commands in a .muxly file to run.
+
+A .muxly file can run commands in its windows, panes and hooks. Until it is
+trusted, opening its directory shows those commands and asks first (see
+settings.muxly_file_policy). Trust is tied to the file's contents: after any
+edit, the file has to be trusted again.
+
+PATH is a directory containing a .muxly file, or the file itself. It
+defaults to the current directory.
+
+Examples:
+  muxly trust                  # Trust ./.muxly
+  muxly trust ~/Dev/api
+  muxly trust list             # Show trusted files`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := muxlyFileArg(args)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no .muxly file at %s", path)
+		} else if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		layout, err := session.ParseMuxlyFile(path, data)
+		if err != nil {
+			return fmt.Errorf("refusing to trust a broken .muxly file: %w", err)
+		}
+		if commands := session.Commands(layout); len(commands) > 0 {
+			fmt.Println("Commands allowed to run:")
+			for _, command := range commands {
+				fmt.Println("  " + command)
+			}
+		}
+
+		if err := trustMuxlyFile(path, data); err != nil {
+			return err
+		}
+		fmt.Println("Trusted", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trustCmd)
+}
+
+// muxlyFileArg returns the absolute path of the .muxly file named by the
+// optional PATH argument: a directory holding one, or the file itself.
+func muxlyFileArg(args []string) (string, error) {
+	arg := "."
+	if len(args) == 1 {
+		arg = args[0]
+	}
+
+	expanded, err := homedir.Expand(os.ExpandEnv(arg))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", arg, err)
+	}
+	path, err := filepath.Abs(expanded)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", arg, err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, session.MuxlyFileName)
+	}
+	return path, nil
+}
+
+// trustMuxlyFile records the .muxly file at path as trusted with contents
+// data.
+func trustMuxlyFile(path string, data []byte) error {
+	store, err := trust.LoadDefault()
+	if err != nil {
+		return fmt.Errorf("failed to load trust store: %w", err)
+	}
+
+	store.Trust(path, data, time.Now())
+	if err := store.Save(); err != nil {
+		return fmt.Errorf("failed to save trust store: %w", err)
+	}
+	return nil
+}
+
+// loadMuxlyFile returns the layout of the .muxly file in dir, or an empty
+// layout when there is none or it is not used.
+//
+// A file that cannot be read or parsed is reported, and the user chooses
+// between falling back to the other layouts and aborting with errAborted.
+//
+// A file that would run commands is only used once trusted. An untrusted or
+// changed file is shown and confirmed first when settings.muxly_file_policy
+// is "prompt" and a terminal is available; otherwise it is ignored with a
+// warning, unless the policy is "allow".
+func loadMuxlyFile(dir string) (models.SessionLayout, error) {
+	path := filepath.Join(dir, session.MuxlyFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return models.SessionLayout{}, nil
+	} else if err != nil {
+		return models.SessionLayout{}, confirmMuxlyFallback(fmt.Errorf("failed to read %s: %w", path, err))
+	}
+
+	layout, err := session.ParseMuxlyFile(path, data)
+	if err != nil {
+		return models.SessionLayout{}, confirmMuxlyFallback(err)
+	}
+	commands := session.Commands(layout)
+	if len(commands) == 0 || cfg.Settings.MuxlyFilePolicy == config.MuxlyFileAllow {
+		return layout, nil
+	}
+
+	store, err := trust.LoadDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load trust store, ignoring %s: %v\n", path, err)
+		return models.SessionLayout{}, nil
+	}
+	status := store.Status(path, data)
+	if status == trust.Trusted {
+		return layout, nil
+	}
+
+	if cfg.Settings.MuxlyFilePolicy == config.MuxlyFileIgnore || !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s .muxly file %s (run 'muxly trust %s' to use it)\n", status, path, dir)
+		return models.SessionLayout{}, nil
+	}
+
+	title := "Trust this .muxly file?"
+	if status == trust.Changed {
+		title = "This .muxly file changed since you trusted it. Trust it again?"
+	}
+	description := fmt.Sprintf("%s runs:\n  %s", path, strings.Join(commands, "\n  "))
+
+	var confirmed bool
+	if err := forms.ConfirmationForm(title, description, &confirmed).Run(); err != nil {
+		return models.SessionLayout{}, fmt.Errorf("failed to run confirmation form: %w", err)
+	}
+	if !confirmed {
+		fmt.Printf("Ignoring %s\n", path)
+		return models.SessionLayout{}, nil
+	}
+
+	if err := trustMuxlyFile(path, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return layout, nil
+}
+
+// errAborted is returned when the user declines to go on after a problem.
+var errAborted = errors.New("aborted")
+
+// confirmMuxlyFallback warns about a broken .muxly file and asks whether to
+// continue without it, returning errAborted if not. Without a terminal it
+// always continues.
+func confirmMuxlyFallback(problem error) error {
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", problem)
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprintln(os.Stderr, "Warning: ignoring the .muxly file and using the default layout")
+		return nil
+	}
+
+	var proceed bool
+	form := forms.ConfirmationForm("Continue without the .muxly file?", "The session will use the template layout instead.", &proceed)
+	if err := form.Run(); err != nil {
+		return fmt.Errorf("failed to run confirmation form: %w", err)
+	}
+	if !proceed {
+		return errAborted
+	}
+	return nil
+}
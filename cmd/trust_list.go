@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Pairadux/muxly/internal/trust"
+
+	"github.com/spf13/cobra"
+)
+
+// trustListCmd lists trusted .muxly files
+var trustListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List trusted .muxly files",
+	Long: `List the trusted .muxly files, with the date each was trusted.
+
+Files edited since they were trusted are marked "changed" and files that no
+longer exist "missing"; neither runs commands until trusted again.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := trust.LoadDefault()
+		if err != nil {
+			return fmt.Errorf("failed to load trust store: %w", err)
+		}
+
+		paths := store.Paths()
+		if len(paths) == 0 {
+			fmt.Println("No trusted .muxly files.")
+			return nil
+		}
+
+		for _, path := range paths {
+			status := "missing"
+			if data, err := os.ReadFile(path); err == nil {
+				status = store.Status(path, data).String()
+			}
+			fmt.Printf("%-8s %s  %s\n", status, store.Entries[path].TrustedAt.Format("2006-01-02"), path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	trustCmd.AddCommand(trustListCmd)
+}
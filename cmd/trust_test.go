@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+)
+
+func TestLoadMuxlyFileTrust(t *testing.T) {
+	withCommands := "windows:\n  - name: editor\n    cmd: nvim\n"
+	tests := []struct {
+		name        string
+		contents    string
+		policy      string
+		trusted     bool
+		wantWindows int
+	}{
+		{name: "untrusted file is ignored without a terminal", contents: withCommands, policy: config.MuxlyFilePrompt},
+		{name: "ignore policy", contents: withCommands, policy: config.MuxlyFileIgnore},
+		{name: "trusted file", contents: withCommands, policy: config.MuxlyFilePrompt, trusted: true, wantWindows: 1},
+		{name: "allow policy", contents: withCommands, policy: config.MuxlyFileAllow, wantWindows: 1},
+		{name: "file without commands needs no trust", contents: "windows:\n  - name: a\n  - name: b\n", policy: config.MuxlyFilePrompt, wantWindows: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeTmux(t, "")
+			cfg.Settings.MuxlyFilePolicy = tt.policy
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, ".muxly")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.trusted {
+				if err := trustMuxlyFile(path, []byte(tt.contents)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			layout, err := loadMuxlyFile(dir)
+			if err != nil {
+				t.Fatalf("loadMuxlyFile() error = %v", err)
+			}
+			if len(layout.Windows) != tt.wantWindows {
+				t.Errorf("loadMuxlyFile() windows = %+v, want %d", layout.Windows, tt.wantWindows)
+			}
+		})
+	}
+}
+
+func TestLoadMuxlyFileChangedAfterTrust(t *testing.T) {
+	useFakeTmux(t, "")
+	cfg.Settings.MuxlyFilePolicy = config.MuxlyFilePrompt
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".muxly")
+	original := []byte("windows:\n  - name: editor\n    cmd: nvim\n")
+	if err := os.WriteFile(path, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := trustMuxlyFile(path, original); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("windows:\n  - name: editor\n    cmd: rm -rf ~\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout, err := loadMuxlyFile(dir)
+	if err != nil {
+		t.Fatalf("loadMuxlyFile() error = %v", err)
+	}
+	if len(layout.Windows) != 0 {
+		t.Errorf("edited .muxly file was used without trusting it again: %+v", layout.Windows)
+	}
+}
+
+func TestLoadMuxlyFileBrokenFallsBack(t *testing.T) {
+	useFakeTmux(t, "")
+	cfg.Settings.MuxlyFilePolicy = config.MuxlyFileAllow
+
+	for _, contents := range []string{
+		"wndows:\n  - name: editor\n",
+		"windows:\n  - name: editor\n    panes:\n      - split: horiz\n",
+	} {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, ".muxly"), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Without a terminal, a broken file is reported and skipped.
+		layout, err := loadMuxlyFile(dir)
+		if err != nil {
+			t.Fatalf("loadMuxlyFile(%q) error = %v", contents, err)
+		}
+		if len(layout.Windows) != 0 {
+			t.Errorf("loadMuxlyFile(%q) windows = %+v, want none", contents, layout.Windows)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Pairadux/muxly/internal/session"
+	"github.com/Pairadux/muxly/internal/trust"
+
+	"github.com/spf13/cobra"
+)
+
+// untrustCmd stops the commands in a .muxly file from running
+var untrustCmd = &cobra.Command{
+	Use:   "untrust [PATH]",
+	Short: "Stop trusting a .muxly file",
+	Long: `Stop trusting a .muxly file, so its commands are confirmed again before
+they run.
+
+PATH is a directory containing a .muxly file, or the file itself. It
+defaults to the current directory. The file does not need to exist anymore.
+
+Examples:
+  muxly untrust
+  muxly untrust ~/Dev/api`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := muxlyFileArg(args)
+		if err != nil {
+			return err
+		}
+
+		store, err := trust.LoadDefault()
+		if err != nil {
+			return fmt.Errorf("failed to load trust store: %w", err)
+		}
+		// A directory that no longer exists cannot be told from a file,
+		// so try it both ways.
+		if !store.Untrust(path) && !store.Untrust(filepath.Join(path, session.MuxlyFileName)) {
+			fmt.Printf("%s was not trusted\n", path)
+			return nil
+		}
+		if err := store.Save(); err != nil {
+			return fmt.Errorf("failed to save trust store: %w", err)
+		}
+
+		fmt.Println("Untrusted", path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(untrustCmd)
+}
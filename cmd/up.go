@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// upCmd starts sessions for configured projects in the background
+var upCmd = &cobra.Command{
+	Use:   "up NAME...",
+	Short: "Start sessions for configured projects in the background",
+	Long: `Start sessions for configured projects in the background, without switching
+or attaching to any of them, e.g. from a login script or cron job.
+
+Each NAME is the session name of a directory from scan_dirs or entry_dirs,
+as listed by 'muxly list --dirs-only'. Its layout is chosen as in the
+picker. Projects that already run are skipped. A project that cannot be
+started does not stop the others.
+
+Examples:
+  muxly up api web notes`,
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projects := make(map[string]models.DirEntry)
+		for _, entry := range newBuilder().DirEntries(0) {
+			projects[entry.SessionName] = entry
+		}
+
+		failed := 0
+		for _, name := range args {
+			entry, ok := projects[name]
+			if !ok {
+				fmt.Printf("Skipped %s: not a configured project\n", name)
+				failed++
+				continue
+			}
+			if tmuxDriver.HasSession(name) {
+				fmt.Printf("Skipped %s: already running\n", name)
+				continue
+			}
+
+			sess, err := sessionForEntry(name, entry, nil)
+			if errors.Is(err, errAborted) {
+				fmt.Printf("Skipped %s: aborted\n", name)
+				continue
+			}
+			if err == nil {
+				err = tmux.CreateDetachedSession(tmuxDriver, &cfg, sess)
+			}
+			if err != nil {
+				fmt.Printf("Failed to start %s: %v\n", name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("Started session %s\n", name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d sessions could not be started", failed, len(args))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upCmd)
+}
+
+// completeProjectNames completes the session names of configured projects
+// that are not running yet.
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	running := tmux.GetTmuxSessionSet(tmuxDriver)
+	var names []string
+	for _, entry := range newBuilder().DirEntries(0) {
+		if !running[entry.SessionName] && !slices.Contains(args, entry.SessionName) {
+			names = append(names, entry.SessionName)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestUpStartsProjectsInTheBackground(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	driver := useFakeTmux(t, "", "web")
+
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web", "docs"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg.ScanDirs = []models.ScanDir{{Path: root}}
+	cfg.Templates = []models.SessionTemplate{
+		{Name: "default", Default: true, Windows: []models.Window{{Name: "shell"}}},
+	}
+
+	err := upCmd.RunE(upCmd, []string{"api", "web", "nope", "docs"})
+	if err == nil {
+		t.Error("up succeeded although nope is not a project")
+	}
+
+	for _, name := range []string{"api", "docs"} {
+		s, ok := driver.Session(name)
+		if !ok {
+			t.Errorf("session %s was not started; sessions = %v", name, tmux.GetTmuxSessionNames(driver))
+			continue
+		}
+		if s.Path != filepath.Join(root, name) || len(s.Windows) != 1 {
+			t.Errorf("session %s = %+v, want the default template in its directory", name, s)
+		}
+	}
+	if len(driver.Switches) != 0 {
+		t.Errorf("Switches = %v, want none", driver.Switches)
+	}
+	if got := len(driver.Sessions); got != 3 {
+		t.Errorf("%d sessions, want web, api and docs", got)
+	}
+}
+
+func TestRootDetached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	driver := useFakeTmux(t, "main", "main")
+
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ScanDirs = []models.ScanDir{{Path: root}}
+	cfg.Templates = []models.SessionTemplate{
+		{Name: "default", Default: true, Windows: []models.Window{{Name: "shell"}}},
+	}
+
+	detached = true
+	t.Cleanup(func() { detached = false })
+	if err := rootCmd.RunE(rootCmd, []string{"api"}); err != nil {
+		t.Fatalf("muxly --detached api error = %v", err)
+	}
+
+	if !driver.HasSession("api") {
+		t.Errorf("session api was not created; sessions = %v", tmux.GetTmuxSessionNames(driver))
+	}
+	if driver.Current != "main" || len(driver.Switches) != 0 {
+		t.Errorf("client switched to %q (%v), want it to stay in main", driver.Current, driver.Switches)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceCmd represents the workspace command
+var workspaceCmd = &cobra.Command{
+	Use:     "workspace",
+	Aliases: []string{"ws"},
+	Short:   "Start and stop groups of sessions together",
+	Long: `Start and stop the workspaces in the configuration file: named groups of
+sessions that are launched together.
+
+Subcommands:
+  list    - List workspaces and which of their sessions run
+  up      - Start every session of a workspace in the background
+  down    - Kill every session of a workspace
+  switch  - Start a workspace and switch to its first session
+
+Examples:
+  muxly workspace list
+  muxly workspace up daily
+  muxly workspace switch daily
+  muxly workspace down daily`,
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+}
+
+// workspaceSession is a workspace member resolved to the session it opens.
+// Err is set when the member cannot be resolved.
+type workspaceSession struct {
+	Member models.WorkspaceMember
+	Name   string
+	Entry  models.DirEntry
+	Err    error
+}
+
+// findWorkspace returns the named workspace from the config.
+func findWorkspace(name string) (models.Workspace, error) {
+	i := slices.IndexFunc(cfg.Workspaces, func(ws models.Workspace) bool { return ws.Name == name })
+	if i < 0 {
+		return models.Workspace{}, fmt.Errorf("workspace %q not found", name)
+	}
+	return cfg.Workspaces[i], nil
+}
+
+// resolveWorkspace finds the directory and session name of each member of
+// ws. Path members are named as muxly open would name them; named members
+// must be directories from scan_dirs or entry_dirs. A member whose directory
+// an earlier member already opens is an error.
+func resolveWorkspace(ws models.Workspace) []workspaceSession {
+	dirs := newBuilder().DirEntries(0)
+
+	sessions := make([]workspaceSession, 0, len(ws.Members))
+	taken := make(map[string]string)
+	claimed := make(map[string]string)
+	for _, member := range ws.Members {
+		s := workspaceSession{Member: member}
+		if member.IsPath() {
+			dir, err := openPath(member.Entry)
+			if err != nil {
+				s.Err = err
+			} else {
+				s.Entry = models.DirEntry{Path: dir}
+				if i := slices.IndexFunc(dirs, func(e models.DirEntry) bool { return e.Path == dir }); i >= 0 {
+					s.Entry = dirs[i]
+				}
+				s.Name, s.Err = openSessionName(dir, "", s.Entry.SessionName)
+				// Members not running yet must not share a name either.
+				for base, n := s.Name, 2; s.Err == nil && taken[s.Name] != "" && taken[s.Name] != dir; n++ {
+					s.Name = fmt.Sprintf("%s-%d", base, n)
+				}
+			}
+		} else {
+			i := slices.IndexFunc(dirs, func(e models.DirEntry) bool { return e.SessionName == member.Entry })
+			if i < 0 {
+				s.Err = fmt.Errorf("%q is not a configured project", member.Entry)
+			} else {
+				s.Entry, s.Name = dirs[i], member.Entry
+			}
+		}
+		if s.Err == nil {
+			// A named member and a path member may still be the same directory.
+			if other, ok := claimed[s.Entry.Path]; ok {
+				s.Err = fmt.Errorf("same directory as member %q", other)
+			} else {
+				claimed[s.Entry.Path] = member.Entry
+				taken[s.Name] = s.Entry.Path
+			}
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// startWorkspace starts every member of ws that is not running, without
+// switching to any of them, and prints the outcome of each. With ws.Group,
+// the new sessions join the group of the first running member. It returns
+// the first member session that runs afterwards and the number of members
+// that could not be started.
+func startWorkspace(ws models.Workspace) (first string, failed int) {
+	for _, s := range resolveWorkspace(ws) {
+		if s.Err != nil {
+			fmt.Printf("Failed to start %s: %v\n", s.Member.Entry, s.Err)
+			failed++
+			continue
+		}
+		if tmuxDriver.HasSession(s.Name) {
+			fmt.Printf("Skipped %s: already running\n", s.Name)
+			if first == "" {
+				first = s.Name
+			}
+			continue
+		}
+
+		var tmpl *models.SessionTemplate
+		if s.Member.Template != "" {
+			if found, ok := config.FindTemplateByName(&cfg, s.Member.Template); ok {
+				tmpl = &found
+			}
+		}
+		sess, err := sessionForEntry(s.Name, s.Entry, tmpl)
+		if errors.Is(err, errAborted) {
+			fmt.Printf("Skipped %s: aborted\n", s.Name)
+			continue
+		}
+		if err == nil {
+			if ws.Group {
+				sess.Group = first
+			}
+			err = tmux.CreateDetachedSession(tmuxDriver, &cfg, sess)
+		}
+		if err != nil {
+			fmt.Printf("Failed to start %s: %v\n", s.Name, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("Started session %s\n", s.Name)
+		if first == "" {
+			first = s.Name
+		}
+	}
+	return first, failed
+}
+
+// completeWorkspaceNames completes the first argument with workspace names.
+func completeWorkspaceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := make([]string, 0, len(cfg.Workspaces))
+	for _, ws := range cfg.Workspaces {
+		names = append(names, ws.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceDownCmd kills every session of a workspace
+var workspaceDownCmd = &cobra.Command{
+	Use:   "down NAME",
+	Short: "Kill every session of a workspace",
+	Long: `Kill every running session of a workspace, running their on_kill hooks.
+
+The session muxly runs in, if it is a member, is killed last. A member that
+cannot be killed does not stop the others.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorkspaceNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := findWorkspace(args[0])
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		current := tmuxDriver.CurrentSession()
+		killCurrent := false
+		for _, s := range resolveWorkspace(ws) {
+			switch {
+			case s.Err != nil || !tmuxDriver.HasSession(s.Name):
+				continue
+			case s.Name == current:
+				killCurrent = true
+			default:
+				names = append(names, s.Name)
+			}
+		}
+		if killCurrent {
+			names = append(names, current)
+		}
+		if len(names) == 0 {
+			fmt.Printf("No session of workspace %q is running\n", ws.Name)
+			return nil
+		}
+
+		failed := 0
+		for _, name := range names {
+			if err := tmux.KillSession(tmuxDriver, &cfg, name); err != nil {
+				fmt.Printf("Failed to kill %s: %v\n", name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("Killed session %s\n", name)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of %d sessions could not be killed", failed, len(names))
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceDownCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceListCmd lists the configured workspaces
+var workspaceListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List workspaces",
+	Long: `List the workspaces in the configuration file, each followed by its
+sessions and whether they run.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(cfg.Workspaces) == 0 {
+			fmt.Println("No workspaces configured.")
+			return nil
+		}
+
+		for i, ws := range cfg.Workspaces {
+			if i > 0 {
+				fmt.Println()
+			}
+			sessions := resolveWorkspace(ws)
+
+			running := 0
+			for _, s := range sessions {
+				if s.Err == nil && tmuxDriver.HasSession(s.Name) {
+					running++
+				}
+			}
+			details := fmt.Sprintf("%d session(s), %d running", len(sessions), running)
+			if ws.Group {
+				details += ", grouped"
+			}
+			fmt.Printf("%s  %s\n", ws.Name, details)
+
+			width := 0
+			for _, s := range sessions {
+				width = max(width, len(s.Member.Entry))
+			}
+			for _, s := range sessions {
+				status := "stopped"
+				switch {
+				case s.Err != nil:
+					status = "error: " + s.Err.Error()
+				case tmuxDriver.HasSession(s.Name):
+					status = "running as " + s.Name
+				}
+				if s.Member.Template != "" {
+					status += ", template " + s.Member.Template
+				}
+				fmt.Printf("  %-*s  %s\n", width, s.Member.Entry, status)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceListCmd)
+}
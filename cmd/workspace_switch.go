@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Pairadux/muxly/internal/tmux"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceSwitchCmd starts a workspace and switches to it
+var workspaceSwitchCmd = &cobra.Command{
+	Use:   "switch NAME",
+	Short: "Start a workspace and switch to its first session",
+	Long: `Start every session of a workspace that is not running, as with
+'muxly workspace up', then switch to the workspace's first session (or
+attach to it outside tmux).`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorkspaceNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := findWorkspace(args[0])
+		if err != nil {
+			return err
+		}
+
+		first, failed := startWorkspace(ws)
+		if first == "" {
+			return fmt.Errorf("no session of workspace %q could be started", ws.Name)
+		}
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d of %d sessions could not be started\n", failed, len(ws.Members))
+		}
+
+		if err := tmux.SwitchToExistingSession(tmuxDriver, &cfg, first); err != nil {
+			if errors.Is(err, tmux.ErrGracefulExit) {
+				return nil
+			}
+			return fmt.Errorf("Failed to switch session: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceSwitchCmd)
+}
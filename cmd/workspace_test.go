@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+// useWorkspace sets up a fake tmux server and a scan_dir holding api and web,
+// with ws as the only workspace. It returns the driver and the scan_dir.
+func useWorkspace(t *testing.T, ws models.Workspace, current string, sessions ...string) (*tmux.FakeDriver, string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	driver := useFakeTmux(t, current, sessions...)
+
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg.ScanDirs = []models.ScanDir{{Path: root}}
+	cfg.Templates = []models.SessionTemplate{
+		{Name: "default", Default: true, Windows: []models.Window{{Name: "shell"}}},
+		{Name: "go", Windows: []models.Window{{Name: "editor"}, {Name: "test"}}},
+	}
+	cfg.Workspaces = []models.Workspace{ws}
+	return driver, root
+}
+
+func TestResolveWorkspace(t *testing.T) {
+	other := filepath.Join(t.TempDir(), "api")
+	if err := os.Mkdir(other, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ws := models.Workspace{Name: "daily", Members: []models.WorkspaceMember{
+		{Entry: "api"},
+		{Entry: other},
+		{Entry: "nope"},
+		{Entry: "~/definitely/missing"},
+	}}
+	_, root := useWorkspace(t, ws, "")
+	ws.Members = append(ws.Members, models.WorkspaceMember{Entry: filepath.Join(root, "api")})
+
+	sessions := resolveWorkspace(ws)
+	if len(sessions) != 5 {
+		t.Fatalf("resolveWorkspace() = %+v, want 5 members", sessions)
+	}
+	if s := sessions[0]; s.Err != nil || s.Name != "api" || s.Entry.Path != filepath.Join(root, "api") {
+		t.Errorf("named member = %+v, want session api in the scan_dir", s)
+	}
+	if s := sessions[1]; s.Err != nil || s.Name != "api-2" || s.Entry.Path != other {
+		t.Errorf("path member = %+v, want session api-2 in %s", s, other)
+	}
+	if sessions[2].Err == nil || sessions[3].Err == nil {
+		t.Errorf("unknown members resolved: %+v %+v", sessions[2], sessions[3])
+	}
+	if s := sessions[4]; s.Err == nil {
+		t.Errorf("path of the api member resolved again: %+v", s)
+	}
+}
+
+func TestWorkspaceUpAndDown(t *testing.T) {
+	ws := models.Workspace{Name: "daily", Group: true, Members: []models.WorkspaceMember{
+		{Entry: "api", Template: "go"},
+		{Entry: "web"},
+		{Entry: "nope"},
+	}}
+	driver, _ := useWorkspace(t, ws, "main", "main")
+
+	if err := workspaceUpCmd.RunE(workspaceUpCmd, []string{"daily"}); err == nil {
+		t.Error("up succeeded although nope is not a project")
+	}
+
+	api, ok := driver.Session("api")
+	if !ok || len(api.Windows) != 2 || api.Group != "" {
+		t.Errorf("api = %+v, want the go template, heading the group", api)
+	}
+	web, ok := driver.Session("web")
+	if !ok || len(web.Windows) != 1 || web.Group != "api" {
+		t.Errorf("web = %+v, want the default template, grouped with api", web)
+	}
+	if driver.Current != "main" || len(driver.Switches) != 0 {
+		t.Errorf("client moved to %q (%v), want it to stay in main", driver.Current, driver.Switches)
+	}
+
+	if err := workspaceDownCmd.RunE(workspaceDownCmd, []string{"daily"}); err != nil {
+		t.Fatalf("down error = %v", err)
+	}
+	if got, want := tmux.GetTmuxSessionNames(driver), []string{"main"}; !slices.Equal(got, want) {
+		t.Errorf("sessions after down = %v, want %v", got, want)
+	}
+}
+
+func TestWorkspaceSwitch(t *testing.T) {
+	ws := models.Workspace{Name: "daily", Members: []models.WorkspaceMember{{Entry: "web"}, {Entry: "api"}}}
+	driver, _ := useWorkspace(t, ws, "main", "main", "api")
+
+	if err := workspaceSwitchCmd.RunE(workspaceSwitchCmd, []string{"daily"}); err != nil {
+		t.Fatalf("switch error = %v", err)
+	}
+	if got, want := tmux.GetTmuxSessionNames(driver), []string{"main", "api", "web"}; !slices.Equal(got, want) {
+		t.Errorf("sessions = %v, want %v", got, want)
+	}
+	if driver.Current != "web" {
+		t.Errorf("Current = %q, want the first member web", driver.Current)
+	}
+}
+
+func TestWorkspaceDownFromInsideWorkspace(t *testing.T) {
+	ws := models.Workspace{Name: "daily", Members: []models.WorkspaceMember{{Entry: "api"}, {Entry: "web"}}}
+	driver, _ := useWorkspace(t, ws, "api", "api", "web")
+
+	if err := workspaceDownCmd.RunE(workspaceDownCmd, []string{"daily"}); err != nil {
+		t.Fatalf("down error = %v", err)
+	}
+	if got := tmux.GetTmuxSessionNames(driver); len(got) != 0 {
+		t.Errorf("sessions left after down: %v", got)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// workspaceUpCmd starts every session of a workspace
+var workspaceUpCmd = &cobra.Command{
+	Use:   "up NAME",
+	Short: "Start every session of a workspace in the background",
+	Long: `Start every session of a workspace in the background, without switching
+or attaching to any of them.
+
+Sessions that already run are skipped. A member that cannot be started does
+not stop the others. With group: true, the new sessions join the tmux
+session group of the first member, so every member shows all of the
+workspace's windows.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeWorkspaceNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := findWorkspace(args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, failed := startWorkspace(ws); failed > 0 {
+			return fmt.Errorf("%d of %d sessions could not be started", failed, len(ws.Members))
+		}
+		return nil
+	},
+}
+
+func init() {
+	workspaceCmd.AddCommand(workspaceUpCmd)
+}
@@ -1,6 +1,11 @@
 package checks
 
-import "testing"
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestCountByStatus(t *testing.T) {
 	tests := []struct {
@@ -159,3 +164,46 @@ func TestHasErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckMuxlyFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"good":   "windows:\n  - name: editor\n",
+		"broken": "windows:\n  - name: editor\n    comand: nvim\n",
+		"split":  "windows:\n  - name: editor\n    panes:\n      - split: horiz\n",
+		"none":   "",
+	}
+	var dirs []string
+	for name, contents := range files {
+		dir := filepath.Join(root, name)
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if contents != "" {
+			if err := os.WriteFile(filepath.Join(dir, ".muxly"), []byte(contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+
+	hints := make(map[string]string)
+	for _, r := range CheckMuxlyFiles(dirs) {
+		if r.Status != StatusError {
+			t.Errorf("CheckMuxlyFiles() result %+v, want only errors", r)
+		}
+		hints[r.Message] = r.Hint
+	}
+	want := map[string]string{
+		filepath.Join(root, "broken", ".muxly") + " is invalid": "line 3: unknown key comand",
+		filepath.Join(root, "split", ".muxly") + " is invalid":  `window "editor" pane 1 has invalid split "horiz" (use horizontal or vertical)`,
+	}
+	if !maps.Equal(hints, want) {
+		t.Errorf("CheckMuxlyFiles() hints = %v, want %v", hints, want)
+	}
+
+	results := CheckMuxlyFiles([]string{filepath.Join(root, "good"), filepath.Join(root, "none")})
+	if len(results) != 1 || results[0].Status != StatusOK || results[0].Message != "1 .muxly file(s) valid" {
+		t.Errorf("CheckMuxlyFiles() = %+v, want one valid file", results)
+	}
+}
@@ -118,6 +118,17 @@ func ValidateConfig(cfg *models.Config) []CheckResult {
 		})
 	}
 
+	for _, tmpl := range cfg.Templates {
+		if err := config.ValidateWindows(tmpl.Windows); err != nil {
+			results = append(results, CheckResult{
+				Name:    "template_windows",
+				Status:  StatusError,
+				Message: fmt.Sprintf("Template %q has invalid windows", tmpl.Name),
+				Hint:    err.Error(),
+			})
+		}
+	}
+
 	if cfg.Settings.TmuxBase != 0 && cfg.Settings.TmuxBase != 1 {
 		results = append(results, CheckResult{
 			Name:    "tmux_base",
@@ -5,18 +5,25 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/picker"
+	"github.com/Pairadux/muxly/internal/tmux"
 )
 
-// VerifyExternalUtils checks for required external tools (tmux, fzf).
+// VerifyExternalUtils checks for required external tools (tmux, and fzf
+// unless pickerKind selects the built-in picker).
 // Returns an error if any required tools are missing.
-func VerifyExternalUtils() error {
+func VerifyExternalUtils(pickerKind string) error {
 	var missing []string
 
 	if _, err := exec.LookPath("tmux"); err != nil {
 		missing = append(missing, "tmux")
 	}
-	if _, err := exec.LookPath("fzf"); err != nil {
-		missing = append(missing, "fzf")
+	if picker.Resolve(pickerKind) == config.PickerFzf {
+		if _, err := exec.LookPath("fzf"); err != nil {
+			missing = append(missing, "fzf")
+		}
 	}
 
 	if len(missing) > 0 {
@@ -64,10 +71,74 @@ func checkTool(name, versionFlag, missingHint string) CheckResult {
 }
 
 // CheckExternalUtils returns detailed check results for external tools.
-func CheckExternalUtils() []CheckResult {
+func CheckExternalUtils(pickerKind string) []CheckResult {
 	return []CheckResult{
 		checkTool("tmux", "-V", "Install tmux: https://github.com/tmux/tmux"),
-		checkTool("fzf", "--version", "Install fzf: https://github.com/junegunn/fzf"),
+		checkPicker(pickerKind),
+	}
+}
+
+// checkPicker checks fzf when pickerKind needs it. With "auto" a missing fzf
+// is only a warning, since the built-in picker is used instead.
+func checkPicker(pickerKind string) CheckResult {
+	const fzfHint = "Install fzf: https://github.com/junegunn/fzf"
+
+	switch pickerKind {
+	case config.PickerBuiltin:
+		return CheckResult{
+			Name:    "picker",
+			Status:  StatusOK,
+			Message: "Picker",
+			Detail:  "(built-in)",
+		}
+	case config.PickerFzf:
+		return checkTool("fzf", "--version", fzfHint)
+	}
+
+	if picker.Resolve(pickerKind) == config.PickerBuiltin {
+		return CheckResult{
+			Name:    "fzf",
+			Status:  StatusWarning,
+			Message: "fzf not found, using built-in picker",
+			Hint:    fzfHint,
+		}
+	}
+	return checkTool("fzf", "--version", fzfHint)
+}
+
+// CheckTmuxServer reports which tmux server d talks to and whether it is
+// running. socket describes the server for the report, "" meaning the default
+// one. A stopped server is fine, since creating a session starts it.
+func CheckTmuxServer(d tmux.Driver, socket string) CheckResult {
+	if socket == "" {
+		socket = "default socket"
+	}
+
+	if !d.ServerRunning() {
+		return CheckResult{
+			Name:    "tmux server",
+			Status:  StatusOK,
+			Message: "tmux server not running",
+			Detail:  fmt.Sprintf("(%s)", socket),
+		}
+	}
+
+	sessions, err := d.ListSessions()
+	if err != nil {
+		return CheckResult{
+			Name:    "tmux server",
+			Status:  StatusWarning,
+			Message: "tmux server is running but its sessions could not be listed",
+			Detail:  fmt.Sprintf("(%s)", socket),
+			Hint:    err.Error(),
+		}
+	}
+
+	return CheckResult{
+		Name:    "tmux server",
+		Status:  StatusOK,
+		Message: "tmux server",
+		Detail:  fmt.Sprintf("(%s, %d sessions)", socket, len(sessions)),
 	}
 }
 
@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/session"
+)
+
+// CheckMuxlyFiles parses the .muxly file of each directory in dirs, including
+// the pane and layout checks templates get, and reports the ones that are
+// broken, or a single OK result if none are.
+func CheckMuxlyFiles(dirs []string) []CheckResult {
+	var results []CheckResult
+	found := 0
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, session.MuxlyFileName)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		found++
+
+		_, err := session.LoadMuxlyFile(dir)
+		if err == nil {
+			continue
+		}
+
+		hint := err.Error()
+		var parseErr *session.ParseError
+		if errors.As(err, &parseErr) {
+			problems := make([]string, len(parseErr.Problems))
+			for i, p := range parseErr.Problems {
+				problems[i] = p.String()
+			}
+			hint = strings.Join(problems, "; ")
+		}
+		results = append(results, CheckResult{
+			Name:    "muxly file",
+			Status:  StatusError,
+			Message: fmt.Sprintf("%s is invalid", path),
+			Hint:    hint,
+		})
+	}
+
+	if len(results) == 0 {
+		results = append(results, CheckResult{
+			Name:    "muxly file",
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d .muxly file(s) valid", found),
+		})
+	}
+	return results
+}
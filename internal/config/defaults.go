@@ -1,12 +1,11 @@
 package config
 
-import "github.com/Pairadux/muxly/internal/models"
+import (
+	"time"
+
+	"github.com/Pairadux/muxly/internal/models"
+)
 
-// TODO: add config option for "use-absolute-path"
-// This would change the entries from using the basename to using the resolved absolute path in the fzf selector
-// TODO: add config option for "use-home-based-path"
-// Similar to use-absolute-path but shows paths from ~/ rather than /
-// Would need to prioritize one over the other if both are enabled and detail which takes priority
 // TODO: add a config option to remove current session from list of options
 // Might would help with the duplicate problem, especially in conjuction with absolute path config option
 
@@ -16,8 +15,55 @@ const (
 	DefaultTmuxBase                = 0
 	DefaultScanDepth               = 1
 	DefaultAlwaysKillOnLastSession = false
+	DefaultSortOrder               = SortAlphabetical
+	DefaultDisplayMode             = DisplaySuffix
+	DefaultPicker                  = PickerAuto
+	DefaultHookTimeout             = 30 * time.Second
+	DefaultMuxlyFilePolicy         = MuxlyFilePrompt
+)
+
+// Picker ordering modes for settings.sort_order
+const (
+	SortAlphabetical = "alphabetical"
+	SortRecent       = "recent"
+	SortFrecency     = "frecency"
+)
+
+// SortOrders lists the accepted values for settings.sort_order.
+var SortOrders = []string{SortAlphabetical, SortRecent, SortFrecency}
+
+// Picker label modes for settings.display_mode
+const (
+	DisplaySuffix   = "suffix"
+	DisplayBasename = "basename"
+	DisplayHome     = "home"
+	DisplayAbsolute = "absolute"
 )
 
+// DisplayModes lists the accepted values for settings.display_mode.
+var DisplayModes = []string{DisplaySuffix, DisplayBasename, DisplayHome, DisplayAbsolute}
+
+// Picker implementations for settings.picker
+const (
+	PickerFzf     = "fzf"
+	PickerBuiltin = "builtin"
+	PickerAuto    = "auto"
+)
+
+// Pickers lists the accepted values for settings.picker.
+var Pickers = []string{PickerFzf, PickerBuiltin, PickerAuto}
+
+// Policies for settings.muxly_file_policy: what happens to the commands of a
+// .muxly file that has not been trusted with 'muxly trust'
+const (
+	MuxlyFilePrompt = "prompt" // show the commands and ask; ignore the file without a terminal
+	MuxlyFileIgnore = "ignore" // ignore the file without asking
+	MuxlyFileAllow  = "allow"  // run the commands of every .muxly file
+)
+
+// MuxlyFilePolicies lists the accepted values for settings.muxly_file_policy.
+var MuxlyFilePolicies = []string{MuxlyFilePrompt, MuxlyFileIgnore, MuxlyFileAllow}
+
 var (
 	// BaseIgnoreDirs are always filtered during scanning and cannot be overridden by user config.
 	// There is no practical reason to scan inside these directories.
@@ -74,6 +120,10 @@ func NewDefaultConfig() models.Config {
 			DefaultDepth:            DefaultScanDepth,
 			TmuxSessionPrefix:       DefaultTmuxSessionPrefix,
 			AlwaysKillOnLastSession: DefaultAlwaysKillOnLastSession,
+			SortOrder:               DefaultSortOrder,
+			DisplayMode:             DefaultDisplayMode,
+			Picker:                  DefaultPicker,
+			MuxlyFilePolicy:         DefaultMuxlyFilePolicy,
 		},
 	}
 }
@@ -95,4 +145,19 @@ func ApplyDefaults(cfg *models.Config) {
 	if cfg.Settings.DefaultDepth == 0 {
 		cfg.Settings.DefaultDepth = DefaultScanDepth
 	}
+	if cfg.Settings.SortOrder == "" {
+		cfg.Settings.SortOrder = DefaultSortOrder
+	}
+	if cfg.Settings.DisplayMode == "" {
+		cfg.Settings.DisplayMode = DefaultDisplayMode
+	}
+	if cfg.Settings.Picker == "" {
+		cfg.Settings.Picker = DefaultPicker
+	}
+	if cfg.Settings.MuxlyFilePolicy == "" {
+		cfg.Settings.MuxlyFilePolicy = DefaultMuxlyFilePolicy
+	}
+	if cfg.Settings.HookTimeout == 0 {
+		cfg.Settings.HookTimeout = DefaultHookTimeout
+	}
 }
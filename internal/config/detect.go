@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CommonProjectDirs are the directories under the home directory that
+// config init offers as scan_dirs when they exist.
+var CommonProjectDirs = []string{"Dev", "code", "src", "projects"}
+
+// ProjectRoot is a detected directory likely to hold projects.
+type ProjectRoot struct {
+	// Path is the directory in ~/ form, as written to the config.
+	Path string
+	// Dirs is the number of visible directories directly inside it.
+	Dirs int
+}
+
+// DetectProjectRoots returns the CommonProjectDirs that exist in home.
+// Names that lead to the same directory, such as a symlink ~/code -> ~/Dev,
+// are reported once.
+func DetectProjectRoots(home string) []ProjectRoot {
+	var roots []ProjectRoot
+	var seen []os.FileInfo
+	for _, name := range CommonProjectDirs {
+		dir := filepath.Join(home, name)
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		if slices.ContainsFunc(seen, func(fi os.FileInfo) bool { return os.SameFile(fi, info) }) {
+			continue
+		}
+		seen = append(seen, info)
+
+		roots = append(roots, ProjectRoot{Path: "~/" + name, Dirs: countDirs(dir)})
+	}
+	return roots
+}
+
+func countDirs(dir string) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	count := 0
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDetectProjectRoots(t *testing.T) {
+	home := t.TempDir()
+	for _, dir := range []string{"Dev/api", "Dev/web", "Dev/.hidden", "projects", "Documents/notes"} {
+		if err := os.MkdirAll(filepath.Join(home, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(home, "Dev", "README"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// ~/code points at ~/Dev and ~/src is a file; neither is offered.
+	if err := os.Symlink(filepath.Join(home, "Dev"), filepath.Join(home, "code")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, "src"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := DetectProjectRoots(home)
+	want := []ProjectRoot{{Path: "~/Dev", Dirs: 2}, {Path: "~/projects", Dirs: 0}}
+	if !slices.Equal(got, want) {
+		t.Errorf("DetectProjectRoots() = %+v, want %+v", got, want)
+	}
+}
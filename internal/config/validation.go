@@ -3,11 +3,46 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/Pairadux/muxly/internal/fzf"
 	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/utility"
 	"gopkg.in/yaml.v3"
 )
 
+// TmuxLayouts lists the preset layout names accepted by tmux's select-layout.
+var TmuxLayouts = []string{
+	"even-horizontal",
+	"even-vertical",
+	"main-horizontal",
+	"main-vertical",
+	"tiled",
+}
+
+// PaneSplits lists the accepted values for a pane's split direction.
+var PaneSplits = []string{"", "horizontal", "vertical"}
+
+// FzfLayouts lists the accepted values for settings.fzf.layout.
+var FzfLayouts = []string{"default", "reverse", "reverse-list"}
+
+// FzfBorders lists the accepted values for settings.fzf.border.
+var FzfBorders = []string{
+	"rounded", "sharp", "bold", "double", "block", "thinblock",
+	"horizontal", "vertical", "line", "top", "bottom", "left", "right", "none",
+}
+
+// fzfHeightPattern matches fzf --height values such as "40%", "20", "~50%"
+// or "-3".
+var fzfHeightPattern = regexp.MustCompile(`^~?-?\d+%?$`)
+
+// rawLayoutPattern matches the leading part of a raw tmux layout string as
+// printed by #{window_layout}, e.g. "bb62,159x48,0,0{79x48,0,0,0,79x48,80,0,1}".
+var rawLayoutPattern = regexp.MustCompile(`^[0-9a-f]{4},\d+x\d+,\d+,\d+`)
+
 // Validate ensures that the application configuration is valid and complete.
 // It checks that at least one directory is configured for scanning and that
 // exactly one template is marked as default with at least one window.
@@ -33,6 +68,9 @@ func Validate(cfg *models.Config) error {
 		if len(tmpl.Windows) == 0 {
 			return fmt.Errorf("template %q must have at least one window", tmpl.Name)
 		}
+		if err := ValidateWindows(tmpl.Windows); err != nil {
+			return fmt.Errorf("template %q: %w", tmpl.Name, err)
+		}
 		if tmpl.Default {
 			defaultCount++
 		}
@@ -57,6 +95,14 @@ func Validate(cfg *models.Config) error {
 		if scanDir.Template != "" && !seenNames[scanDir.Template] {
 			return fmt.Errorf("scan_dir %q references unknown template %q", scanDir.Path, scanDir.Template)
 		}
+		if scanDir.StopAtMarker && len(scanDir.Markers) == 0 {
+			return fmt.Errorf("scan_dir %q sets stop_at_marker without any markers", scanDir.Path)
+		}
+		for _, marker := range scanDir.Markers {
+			if marker == "" || strings.ContainsRune(marker, '/') {
+				return fmt.Errorf("scan_dir %q has invalid marker %q (use a file or directory name)", scanDir.Path, marker)
+			}
+		}
 	}
 
 	for _, entryDir := range cfg.EntryDirs {
@@ -65,9 +111,113 @@ func Validate(cfg *models.Config) error {
 		}
 	}
 
+	seenWorkspaces := make(map[string]bool)
+	for _, ws := range cfg.Workspaces {
+		if ws.Name == "" {
+			return fmt.Errorf("all workspaces must have a name")
+		}
+		if seenWorkspaces[ws.Name] {
+			return fmt.Errorf("duplicate workspace name %q", ws.Name)
+		}
+		seenWorkspaces[ws.Name] = true
+		if len(ws.Members) == 0 {
+			return fmt.Errorf("workspace %q must have at least one member", ws.Name)
+		}
+		seenMembers := make(map[string]string)
+		for i, member := range ws.Members {
+			if member.Entry == "" {
+				return fmt.Errorf("workspace %q member %d has no entry", ws.Name, i+1)
+			}
+			key := member.Entry
+			if member.IsPath() {
+				if resolved, err := utility.ResolvePath(key); err == nil {
+					key = filepath.Clean(resolved)
+				}
+			}
+			if first, ok := seenMembers[key]; ok {
+				return fmt.Errorf("workspace %q lists %q twice (as %q and %q)", ws.Name, key, first, member.Entry)
+			}
+			seenMembers[key] = member.Entry
+			if member.Template != "" && !seenNames[member.Template] {
+				return fmt.Errorf("workspace %q member %q references unknown template %q", ws.Name, member.Entry, member.Template)
+			}
+		}
+	}
+
+	if cfg.Settings.SortOrder != "" && !slices.Contains(SortOrders, cfg.Settings.SortOrder) {
+		return fmt.Errorf("invalid sort_order %q (use one of %v)", cfg.Settings.SortOrder, SortOrders)
+	}
+	if cfg.Settings.DisplayMode != "" && !slices.Contains(DisplayModes, cfg.Settings.DisplayMode) {
+		return fmt.Errorf("invalid display_mode %q (use one of %v)", cfg.Settings.DisplayMode, DisplayModes)
+	}
+	if cfg.Settings.Picker != "" && !slices.Contains(Pickers, cfg.Settings.Picker) {
+		return fmt.Errorf("invalid picker %q (use one of %v)", cfg.Settings.Picker, Pickers)
+	}
+	if cfg.Settings.MuxlyFilePolicy != "" && !slices.Contains(MuxlyFilePolicies, cfg.Settings.MuxlyFilePolicy) {
+		return fmt.Errorf("invalid muxly_file_policy %q (use one of %v)", cfg.Settings.MuxlyFilePolicy, MuxlyFilePolicies)
+	}
+	if cfg.Settings.TmuxSocketName != "" && cfg.Settings.TmuxSocketPath != "" {
+		return fmt.Errorf("tmux_socket_name and tmux_socket_path are mutually exclusive")
+	}
+	if strings.ContainsRune(cfg.Settings.TmuxSocketName, '/') {
+		return fmt.Errorf("invalid tmux_socket_name %q (use tmux_socket_path for paths)", cfg.Settings.TmuxSocketName)
+	}
+	if cfg.Settings.HookTimeout < 0 {
+		return fmt.Errorf("invalid hook_timeout %s (must be positive)", cfg.Settings.HookTimeout)
+	}
+	if err := ValidateFzfSettings(cfg.Settings.Fzf); err != nil {
+		return fmt.Errorf("settings.fzf: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateFzfSettings checks the values of the settings.fzf block that muxly
+// can verify without running fzf.
+func ValidateFzfSettings(s models.FzfSettings) error {
+	if s.Layout != "" && !slices.Contains(FzfLayouts, s.Layout) {
+		return fmt.Errorf("invalid layout %q (use one of %v)", s.Layout, FzfLayouts)
+	}
+	if s.Border != "" && !slices.Contains(FzfBorders, s.Border) {
+		return fmt.Errorf("invalid border %q (use one of %v)", s.Border, FzfBorders)
+	}
+	if s.Height != "" && !fzfHeightPattern.MatchString(s.Height) {
+		return fmt.Errorf("invalid height %q (use lines or a percentage, e.g. 20 or 40%%)", s.Height)
+	}
+	if _, err := fzf.SplitArgs(s.Args); err != nil {
+		return fmt.Errorf("invalid args: %w", err)
+	}
+	return nil
+}
+
+// ValidateWindows checks the pane and layout definitions of a window list.
+// It is shared by template validation and .muxly layouts.
+func ValidateWindows(windows []models.Window) error {
+	for i, w := range windows {
+		label := w.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if w.Layout != "" && !IsValidLayout(w.Layout) {
+			return fmt.Errorf("window %q has unknown layout %q (use one of %v or a raw layout string)", label, w.Layout, TmuxLayouts)
+		}
+		for j, p := range w.Panes {
+			if !slices.Contains(PaneSplits, p.Split) {
+				return fmt.Errorf("window %q pane %d has invalid split %q (use horizontal or vertical)", label, j+1, p.Split)
+			}
+			if p.Size < 0 || p.Size > 99 {
+				return fmt.Errorf("window %q pane %d has invalid size %d (must be 1-99 percent)", label, j+1, p.Size)
+			}
+		}
+	}
 	return nil
 }
 
+// IsValidLayout reports whether layout is a tmux preset name or a raw layout string.
+func IsValidLayout(layout string) bool {
+	return slices.Contains(TmuxLayouts, layout) || rawLayoutPattern.MatchString(layout)
+}
+
 // ValidateConfigFile reads and validates a config file at the given path.
 // Returns the parsed config if valid, or an error if the file cannot be read or is invalid.
 func ValidateConfigFile(path string) (*models.Config, error) {
@@ -274,6 +274,306 @@ func TestValidate(t *testing.T) {
 			expectError: true,
 			errContains: "entry_dir \"~/Documents\" references unknown template",
 		},
+		{
+			name: "valid panes and preset layout",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{
+						Name:   "main",
+						Cmd:    "nvim",
+						Layout: "main-vertical",
+						Panes: []models.Pane{
+							{Split: "horizontal", Size: 30, Cmd: "go test ./..."},
+							{Split: "vertical", Path: "logs", Cmd: "tail -f app.log"},
+						},
+					}}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "valid raw layout string",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{
+						Name:   "main",
+						Layout: "bb62,159x48,0,0{79x48,0,0,0,79x48,80,0,1}",
+						Panes:  []models.Pane{{Split: "horizontal"}},
+					}}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid unknown layout",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main", Layout: "sideways"}}},
+				},
+			},
+			expectError: true,
+			errContains: "unknown layout",
+		},
+		{
+			name: "invalid pane split",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{
+						Name:  "main",
+						Panes: []models.Pane{{Split: "diagonal"}},
+					}}},
+				},
+			},
+			expectError: true,
+			errContains: "invalid split",
+		},
+		{
+			name: "invalid pane size",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{
+						Name:  "main",
+						Panes: []models.Pane{{Size: 120}},
+					}}},
+				},
+			},
+			expectError: true,
+			errContains: "invalid size",
+		},
+		{
+			name: "valid sort order",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{SortOrder: "frecency"},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid sort order",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{SortOrder: "popular"},
+			},
+			expectError: true,
+			errContains: "invalid sort_order",
+		},
+		{
+			name: "invalid picker",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{Picker: "skim"},
+			},
+			expectError: true,
+			errContains: "invalid picker",
+		},
+		{
+			name: "tmux socket name and path",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{TmuxSocketName: "work", TmuxSocketPath: "/tmp/work.sock"},
+			},
+			expectError: true,
+			errContains: "mutually exclusive",
+		},
+		{
+			name: "tmux socket name with slash",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{TmuxSocketName: "/tmp/work.sock"},
+			},
+			expectError: true,
+			errContains: "invalid tmux_socket_name",
+		},
+		{
+			name: "valid fzf settings",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{Fzf: models.FzfSettings{
+					Height: "~40%", Layout: "reverse", Border: "rounded", Args: "--cycle --prompt='> '", Tmux: "center,80%",
+				}},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid fzf layout",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{Fzf: models.FzfSettings{Layout: "upside-down"}},
+			},
+			expectError: true,
+			errContains: "settings.fzf: invalid layout",
+		},
+		{
+			name: "invalid fzf height",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{Fzf: models.FzfSettings{Height: "half"}},
+			},
+			expectError: true,
+			errContains: "invalid height",
+		},
+		{
+			name: "invalid fzf args quoting",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Settings: models.Settings{Fzf: models.FzfSettings{Args: "--prompt 'oops"}},
+			},
+			expectError: true,
+			errContains: "invalid args",
+		},
+		{
+			name: "valid workspace",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{{Name: "daily", Members: []models.WorkspaceMember{
+					{Entry: "api", Template: "Default"},
+					{Entry: "~/Dev/web"},
+				}}},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid duplicate workspace",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{
+					{Name: "daily", Members: []models.WorkspaceMember{{Entry: "api"}}},
+					{Name: "daily", Members: []models.WorkspaceMember{{Entry: "web"}}},
+				},
+			},
+			expectError: true,
+			errContains: "duplicate workspace",
+		},
+		{
+			name: "invalid empty workspace",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{{Name: "daily"}},
+			},
+			expectError: true,
+			errContains: "at least one member",
+		},
+		{
+			name: "invalid workspace member template",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{{Name: "daily", Members: []models.WorkspaceMember{
+					{Entry: "api", Template: "missing"},
+				}}},
+			},
+			expectError: true,
+			errContains: "unknown template",
+		},
+		{
+			name: "invalid duplicate workspace member",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{{Name: "daily", Members: []models.WorkspaceMember{
+					{Entry: "api"}, {Entry: "web"}, {Entry: "api", Template: "Default"},
+				}}},
+			},
+			expectError: true,
+			errContains: `lists "api" twice`,
+		},
+		{
+			name: "invalid workspace member path listed twice",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{{Path: "~/Dev"}},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+				Workspaces: []models.Workspace{{Name: "daily", Members: []models.WorkspaceMember{
+					{Entry: "~/Dev/api"}, {Entry: "~/Dev/api/"},
+				}}},
+			},
+			expectError: true,
+			errContains: "twice",
+		},
+		{
+			name: "valid scan_dir markers",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{
+					{Path: "~/code", Markers: []string{".git", "go.mod"}, StopAtMarker: true},
+				},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid stop_at_marker without markers",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{
+					{Path: "~/code", StopAtMarker: true},
+				},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+			},
+			expectError: true,
+			errContains: "stop_at_marker without any markers",
+		},
+		{
+			name: "invalid marker path",
+			cfg: &models.Config{
+				ScanDirs: []models.ScanDir{
+					{Path: "~/code", Markers: []string{"src/main.go"}},
+				},
+				Templates: []models.SessionTemplate{
+					{Name: "Default", Default: true, Windows: []models.Window{{Name: "main"}}},
+				},
+			},
+			expectError: true,
+			errContains: "invalid marker",
+		},
 	}
 
 	for _, tt := range tests {
@@ -18,7 +18,17 @@ const (
 	EnvTmux          = "TMUX"
 	EnvShell         = "SHELL"
 	EnvXdgConfigHome = "XDG_CONFIG_HOME"
+	EnvXdgStateHome  = "XDG_STATE_HOME"
+	EnvXdgCacheHome  = "XDG_CACHE_HOME"
 	EnvEditor        = "EDITOR"
+	EnvFzfOpts       = "MUXLY_FZF_OPTS"
+	EnvTmuxSocket    = "MUXLY_TMUX_SOCKET"
+	EnvTmuxTmpdir    = "TMUX_TMPDIR"
+
+	// Environment variables set for hooks
+	EnvHookSession  = "MUXLY_SESSION"
+	EnvHookPath     = "MUXLY_PATH"
+	EnvHookTemplate = "MUXLY_TEMPLATE"
 
 	// Common strings
 	UserCancelledMsg = "user cancelled"
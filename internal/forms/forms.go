@@ -1,6 +1,9 @@
 package forms
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/Pairadux/muxly/internal/models"
 
 	"github.com/charmbracelet/huh"
@@ -36,3 +39,156 @@ func ConfirmationForm(title, description string, confirm *bool) *huh.Form {
 		),
 	)
 }
+
+func InputForm(title, description string, value *string, validate func(string) error) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(title).
+				Description(description).
+				Validate(validate).
+				Value(value),
+		),
+	)
+}
+
+// TemplateForm asks for the settings of a new template. Windows are added
+// separately with WindowForm.
+func TemplateForm(name, label, path *string, isDefault *bool, validateName func(string) error) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Template name").
+				Description("Used in scan_dirs, entry_dirs and muxly template commands").
+				Validate(validateName).
+				Value(name),
+			huh.NewInput().
+				Title("Label").
+				Description("Shown by muxly create instead of the name (optional)").
+				Value(label),
+			huh.NewInput().
+				Title("Directory").
+				Description("Always start sessions here instead of picking a directory (optional)").
+				Value(path),
+			huh.NewConfirm().
+				Title("Make this the default template?").
+				Value(isDefault),
+		),
+	)
+}
+
+// WindowForm asks for a window's name, command and directory.
+func WindowForm(title string, window *models.Window) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Window name").
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("a window needs a name")
+					}
+					return nil
+				}).
+				Value(&window.Name),
+			huh.NewInput().
+				Title("Command").
+				Description("Runs in the window's first pane (optional)").
+				Value(&window.Cmd),
+			huh.NewInput().
+				Title("Directory").
+				Description("Relative to the session directory (optional)").
+				Value(&window.Path),
+		).Title(title),
+	)
+}
+
+// PaneForm asks how to split off an extra pane and what to run in it.
+func PaneForm(title string, pane *models.Pane) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Split").
+				Options(
+					huh.NewOption("Vertical (stacked)", "vertical"),
+					huh.NewOption("Horizontal (side by side)", "horizontal"),
+				).
+				Value(&pane.Split),
+			huh.NewInput().
+				Title("Command").
+				Description("Runs in the new pane (optional)").
+				Value(&pane.Cmd),
+		).Title(title),
+	)
+}
+
+// ScanDirsForm asks which project directories to scan and how deep to look
+// for projects in them.
+func ScanDirsForm(options []huh.Option[string], selected *[]string, depth *int) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which directories hold your projects?").
+				Description("Selected directories are added to scan_dirs").
+				Options(options...).
+				Value(selected),
+		),
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Scan depth").
+				Description("How many levels below a scan directory projects are listed").
+				Options(
+					huh.NewOption("1 - ~/Dev/project", 1),
+					huh.NewOption("2 - ~/Dev/group/project", 2),
+					huh.NewOption("3 - ~/Dev/org/group/project", 3),
+				).
+				Value(depth),
+		),
+	)
+}
+
+// AliasesForm asks for an optional alias for each path. aliases must have
+// the same length as paths; aliases have to be unique.
+func AliasesForm(paths []string, aliases []string) *huh.Form {
+	inputs := make([]huh.Field, len(paths))
+	for i, path := range paths {
+		inputs[i] = huh.NewInput().
+			Title("Alias for " + path).
+			Description("Shown instead of the path in the picker (optional)").
+			Validate(func(s string) error {
+				s = strings.TrimSpace(s)
+				for j, other := range aliases {
+					if j != i && s != "" && strings.TrimSpace(other) == s {
+						return errors.New("alias already used for " + paths[j])
+					}
+				}
+				return nil
+			}).
+			Value(&aliases[i])
+	}
+	return huh.NewForm(huh.NewGroup(inputs...))
+}
+
+// TemplateChoiceForm asks whether to start with the built-in templates or a
+// custom editor, shell and tests layout, and for the custom layout's
+// commands.
+func TemplateChoiceForm(options []huh.Option[string], choice *string, custom string, editorCmd, testCmd *string) *huh.Form {
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Session templates").
+				Description("More can be added later with muxly template add").
+				Options(options...).
+				Value(choice),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Editor command").
+				Description("Runs in the editor window").
+				Value(editorCmd),
+			huh.NewInput().
+				Title("Test command").
+				Description("Runs in the tests window, e.g. go test ./... (optional)").
+				Value(testCmd),
+		).WithHideFunc(func() bool { return *choice != custom }),
+	)
+}
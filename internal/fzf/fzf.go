@@ -9,6 +9,51 @@ import (
 	"github.com/Pairadux/muxly/internal/constants"
 )
 
+// DefaultPreviewWindow is the fzf --preview-window used when a preview
+// command is set.
+const DefaultPreviewWindow = "right,50%,wrap"
+
+// Options configures an fzf invocation. Empty fields keep fzf's defaults.
+type Options struct {
+	Prompt string
+	Height string
+	Layout string
+	Border string
+	Color  string
+
+	// Tmux opens fzf in a tmux popup (--tmux) with the given position and
+	// size, e.g. "center,80%".
+	Tmux string
+
+	// Header is shown above the list.
+	Header string
+
+	// Multi lets the user mark several lines with tab; all of them are
+	// returned in Result.Selections.
+	Multi bool
+
+	// Expect lists keys (e.g. "ctrl-x") that accept the selection like Enter
+	// does, reporting which key was pressed in Result.Key.
+	Expect []string
+
+	// Preview is the command fzf runs for the highlighted line, with {}
+	// replaced by the quoted line. Empty disables the preview.
+	Preview string
+
+	// Args are passed to fzf after all other options, so they take
+	// precedence.
+	Args []string
+}
+
+// Result is the outcome of an fzf selection. Key is the Options.Expect key
+// that accepted the selection, or empty for Enter. Selections holds every
+// selected line, of which Selection is the first.
+type Result struct {
+	Key        string
+	Selection  string
+	Selections []string
+}
+
 // SelectWithFzf presents a list of options to the user via the fzf fuzzy finder
 // and returns the selected option. The options are passed as stdin to the fzf
 // command, allowing the user to interactively filter and select from them.
@@ -16,17 +61,128 @@ import (
 // Returns an error if fzf is not available, if there's an I/O error, or if the
 // user cancels the selection (Ctrl+C), in which case the error message is
 // "user cancelled".
-func SelectWithFzf(options []string) (string, error) {
-	fzf := exec.Command("fzf")
+func SelectWithFzf(options []string, opts Options) (Result, error) {
+	fzf := exec.Command("fzf", buildArgs(opts)...)
 	fzf.Stdin = strings.NewReader(strings.Join(options, "\n"))
 	fzf.Stderr = os.Stderr
-	choice, err := fzf.Output()
+	output, err := fzf.Output()
 	if err != nil {
 		// Exit gracefully if user quits
 		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == constants.FzfUserCancelExitCode {
-			return "", fmt.Errorf(constants.UserCancelledMsg)
+			return Result{}, fmt.Errorf(constants.UserCancelledMsg)
 		}
-		return "", err
+		return Result{}, err
+	}
+	return parseOutput(string(output), len(opts.Expect) > 0), nil
+}
+
+// parseOutput splits fzf's output into the pressed key, which fzf prints on
+// its own line first when --expect is given, and the selected lines.
+func parseOutput(output string, expect bool) Result {
+	var result Result
+	if expect {
+		var key string
+		key, output, _ = strings.Cut(output, "\n")
+		result.Key = strings.TrimSpace(key)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result.Selections = append(result.Selections, line)
+		}
+	}
+	if len(result.Selections) > 0 {
+		result.Selection = result.Selections[0]
+	}
+	return result
+}
+
+// buildArgs translates opts into fzf command-line arguments.
+func buildArgs(opts Options) []string {
+	var args []string
+	for _, opt := range []struct{ flag, value string }{
+		{"--prompt", opts.Prompt},
+		{"--height", opts.Height},
+		{"--layout", opts.Layout},
+		{"--border", opts.Border},
+		{"--color", opts.Color},
+		{"--tmux", opts.Tmux},
+	} {
+		if opt.value != "" {
+			args = append(args, opt.flag+"="+opt.value)
+		}
+	}
+	if opts.Header != "" {
+		args = append(args, "--header", opts.Header)
+	}
+	if opts.Multi {
+		args = append(args, "--multi")
+	}
+	if len(opts.Expect) > 0 {
+		args = append(args, "--expect", strings.Join(opts.Expect, ","))
+	}
+	if opts.Preview != "" {
+		args = append(args, "--preview", opts.Preview, "--preview-window", DefaultPreviewWindow)
+	}
+	return append(args, opts.Args...)
+}
+
+// SplitArgs splits a string of fzf options into arguments the way a POSIX
+// shell would, honoring single quotes, double quotes and backslash escapes.
+// It is used for settings.fzf.args and MUXLY_FZF_OPTS.
+func SplitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inArg = true, true
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if inArg {
+		args = append(args, current.String())
 	}
-	return strings.TrimSpace(string(choice)), nil
+	return args, nil
 }
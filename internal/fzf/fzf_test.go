@@ -0,0 +1,126 @@
+package fzf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "no options",
+			opts: Options{},
+			want: nil,
+		},
+		{
+			name: "preview",
+			opts: Options{Preview: "muxly preview -- {}"},
+			want: []string{"--preview", "muxly preview -- {}", "--preview-window", DefaultPreviewWindow},
+		},
+		{
+			name: "settings and raw args",
+			opts: Options{
+				Prompt:  "> ",
+				Height:  "40%",
+				Layout:  "reverse",
+				Border:  "rounded",
+				Color:   "dark",
+				Tmux:    "center,80%",
+				Preview: "p {}",
+				Args:    []string{"--cycle", "--preview-window=up"},
+			},
+			want: []string{
+				"--prompt=> ", "--height=40%", "--layout=reverse", "--border=rounded", "--color=dark", "--tmux=center,80%",
+				"--preview", "p {}", "--preview-window", DefaultPreviewWindow,
+				"--cycle", "--preview-window=up",
+			},
+		},
+		{
+			name: "header and expect",
+			opts: Options{Header: "ctrl-x: kill", Expect: []string{"ctrl-x", "ctrl-y"}},
+			want: []string{"--header", "ctrl-x: kill", "--expect", "ctrl-x,ctrl-y"},
+		},
+		{
+			name: "multi",
+			opts: Options{Multi: true},
+			want: []string{"--multi"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildArgs(tt.opts); !slices.Equal(got, tt.want) {
+				t.Errorf("buildArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		expect bool
+		want   Result
+	}{
+		{name: "plain", output: "project\n", want: Result{Selection: "project", Selections: []string{"project"}}},
+		{name: "expect enter", output: "\nproject\n", expect: true, want: Result{Selection: "project", Selections: []string{"project"}}},
+		{
+			name:   "expect key",
+			output: "ctrl-x\n[TMUX] work\n",
+			expect: true,
+			want:   Result{Key: "ctrl-x", Selection: "[TMUX] work", Selections: []string{"[TMUX] work"}},
+		},
+		{name: "expect key without match", output: "ctrl-x\n", expect: true, want: Result{Key: "ctrl-x"}},
+		{
+			name:   "multi",
+			output: "\napi\nweb\n[TMUX] docs\n",
+			expect: true,
+			want:   Result{Selection: "api", Selections: []string{"api", "web", "[TMUX] docs"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseOutput(tt.output, tt.expect)
+			if got.Key != tt.want.Key || got.Selection != tt.want.Selection || !slices.Equal(got.Selections, tt.want.Selections) {
+				t.Errorf("parseOutput(%q) = %+v, want %+v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "whitespace only", input: "  \t ", want: nil},
+		{name: "plain", input: "--cycle  --no-sort", want: []string{"--cycle", "--no-sort"}},
+		{name: "single quotes", input: `--prompt='muxly > '`, want: []string{"--prompt=muxly > "}},
+		{name: "double quotes with escape", input: `--header "say \"hi\""`, want: []string{"--header", `say "hi"`}},
+		{name: "backslash space", input: `--prompt=a\ b`, want: []string{"--prompt=a b"}},
+		{name: "empty quoted arg", input: `--prompt ''`, want: []string{"--prompt", ""}},
+		{name: "unterminated quote", input: `--prompt 'oops`, wantErr: true},
+		{name: "trailing backslash", input: `--cycle \`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SplitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
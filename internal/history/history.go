@@ -0,0 +1,196 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// FileName is the name of the history file inside the muxly state directory.
+const FileName = "history.json"
+
+// MaxEntries caps the number of sessions kept in the store. When exceeded,
+// the entries with the lowest frecency are dropped on save.
+const MaxEntries = 500
+
+// Entry records how often and how recently a session was opened.
+type Entry struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Store is a persistent record of the sessions muxly has opened, keyed by
+// tmux session name.
+type Store struct {
+	path    string
+	Entries map[string]Entry `json:"entries"`
+}
+
+// DefaultPath returns the location of the history file,
+// $XDG_STATE_HOME/muxly/history.json.
+func DefaultPath() (string, error) {
+	dir, err := utility.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the history store at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path, Entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return store, fmt.Errorf("reading history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return &Store{path: path, Entries: make(map[string]Entry)}, fmt.Errorf("parsing history %s: %w", path, err)
+	}
+	if store.Entries == nil {
+		store.Entries = make(map[string]Entry)
+	}
+
+	return store, nil
+}
+
+// LoadDefault reads the history store from DefaultPath.
+func LoadDefault() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return &Store{Entries: make(map[string]Entry)}, err
+	}
+	return Load(path)
+}
+
+// Record notes that the named session was opened at now.
+func (s *Store) Record(name string, now time.Time) {
+	entry := s.Entries[name]
+	entry.Count++
+	entry.LastUsed = now
+	s.Entries[name] = entry
+}
+
+// Rename moves the history of session old to new, so a renamed session keeps
+// its place in recent and frecency order. History already recorded for new
+// is merged in.
+func (s *Store) Rename(old, new string) {
+	entry, ok := s.Entries[old]
+	if !ok {
+		return
+	}
+	delete(s.Entries, old)
+
+	if existing, ok := s.Entries[new]; ok {
+		entry.Count += existing.Count
+		if existing.LastUsed.After(entry.LastUsed) {
+			entry.LastUsed = existing.LastUsed
+		}
+	}
+	s.Entries[new] = entry
+}
+
+// Save writes the store back to the path it was loaded from, pruning it to
+// MaxEntries first.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("history store has no path")
+	}
+
+	s.prune(time.Now())
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utility.WriteFileAtomic(s.path, data)
+}
+
+// LastUsed returns when the named session was last opened, or the zero time
+// if it has never been recorded.
+func (s *Store) LastUsed(name string) time.Time {
+	return s.Entries[name].LastUsed
+}
+
+// Score returns the frecency of the named session at now: its open count
+// weighted by how recently it was last opened. Unknown sessions score 0.
+func (s *Store) Score(name string, now time.Time) float64 {
+	entry, ok := s.Entries[name]
+	if !ok {
+		return 0
+	}
+
+	count := float64(entry.Count)
+	age := now.Sub(entry.LastUsed)
+	switch {
+	case age < time.Hour:
+		return count * 4
+	case age < 24*time.Hour:
+		return count * 2
+	case age < 7*24*time.Hour:
+		return count / 2
+	default:
+		return count / 4
+	}
+}
+
+// prune drops the lowest-scoring entries until at most MaxEntries remain.
+func (s *Store) prune(now time.Time) {
+	if len(s.Entries) <= MaxEntries {
+		return
+	}
+
+	names := make([]string, 0, len(s.Entries))
+	for name := range s.Entries {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		scoreA, scoreB := s.Score(a, now), s.Score(b, now)
+		if scoreA != scoreB {
+			if scoreA > scoreB {
+				return -1
+			}
+			return 1
+		}
+		return s.Entries[b].LastUsed.Compare(s.Entries[a].LastUsed)
+	})
+
+	for _, name := range names[MaxEntries:] {
+		delete(s.Entries, name)
+	}
+}
+
+// Record loads the default history store, records the named session and
+// saves it again.
+func Record(name string) error {
+	store, err := LoadDefault()
+	if err != nil {
+		return err
+	}
+	store.Record(name, time.Now())
+	return store.Save()
+}
+
+// Rename loads the default history store, moves the history of session old to
+// new and saves it again.
+func Rename(old, new string) error {
+	store, err := LoadDefault()
+	if err != nil {
+		return err
+	}
+	if _, ok := store.Entries[old]; !ok {
+		return nil
+	}
+	store.Rename(old, new)
+	return store.Save()
+}
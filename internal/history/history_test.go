@@ -0,0 +1,129 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", FileName)
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() on missing file unexpected error: %v", err)
+	}
+	if len(store.Entries) != 0 {
+		t.Fatalf("Load() on missing file returned %d entries, want 0", len(store.Entries))
+	}
+
+	now := time.Now().Truncate(time.Second)
+	store.Record("muxly", now)
+	store.Record("muxly", now)
+	store.Record("dotfiles", now.Add(-time.Hour))
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if got := loaded.Entries["muxly"].Count; got != 2 {
+		t.Errorf("Entries[muxly].Count = %d, want 2", got)
+	}
+	if got := loaded.LastUsed("dotfiles"); !got.Equal(now.Add(-time.Hour)) {
+		t.Errorf("LastUsed(dotfiles) = %v, want %v", got, now.Add(-time.Hour))
+	}
+}
+
+func TestScore(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		entry    Entry
+		expected float64
+	}{
+		{
+			name:     "within the hour",
+			entry:    Entry{Count: 3, LastUsed: now.Add(-10 * time.Minute)},
+			expected: 12,
+		},
+		{
+			name:     "within the day",
+			entry:    Entry{Count: 3, LastUsed: now.Add(-5 * time.Hour)},
+			expected: 6,
+		},
+		{
+			name:     "within the week",
+			entry:    Entry{Count: 3, LastUsed: now.Add(-72 * time.Hour)},
+			expected: 1.5,
+		},
+		{
+			name:     "older than a week",
+			entry:    Entry{Count: 4, LastUsed: now.Add(-30 * 24 * time.Hour)},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{Entries: map[string]Entry{"s": tt.entry}}
+			if got := store.Score("s", now); got != tt.expected {
+				t.Errorf("Score() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	store := &Store{Entries: map[string]Entry{}}
+	if got := store.Score("unknown", now); got != 0 {
+		t.Errorf("Score(unknown) = %v, want 0", got)
+	}
+}
+
+func TestSavePrunesLowestScores(t *testing.T) {
+	store, _ := Load(filepath.Join(t.TempDir(), FileName))
+
+	now := time.Now()
+	for i := 0; i < MaxEntries+10; i++ {
+		store.Entries[fmt.Sprintf("old-%d", i)] = Entry{Count: 1, LastUsed: now.Add(-60 * 24 * time.Hour)}
+	}
+	store.Record("fresh", now)
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	if len(store.Entries) != MaxEntries {
+		t.Errorf("Save() kept %d entries, want %d", len(store.Entries), MaxEntries)
+	}
+	if _, ok := store.Entries["fresh"]; !ok {
+		t.Error("Save() pruned the highest-scoring entry")
+	}
+}
+
+func TestRename(t *testing.T) {
+	now := time.Now()
+	store, _ := Load(filepath.Join(t.TempDir(), FileName))
+	store.Entries["api"] = Entry{Count: 3, LastUsed: now.Add(-time.Hour)}
+	store.Entries["backend"] = Entry{Count: 2, LastUsed: now}
+	store.Entries["web"] = Entry{Count: 1, LastUsed: now}
+
+	store.Rename("api", "api-v1")
+	store.Rename("web", "backend")
+	store.Rename("missing", "other")
+
+	want := map[string]Entry{
+		"api-v1":  {Count: 3, LastUsed: now.Add(-time.Hour)},
+		"backend": {Count: 3, LastUsed: now},
+	}
+	if len(store.Entries) != len(want) {
+		t.Errorf("Entries = %v, want %v", store.Entries, want)
+	}
+	for name, entry := range want {
+		if got := store.Entries[name]; got.Count != entry.Count || !got.LastUsed.Equal(entry.LastUsed) {
+			t.Errorf("Entries[%s] = %+v, want %+v", name, got, entry)
+		}
+	}
+}
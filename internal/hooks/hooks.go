@@ -0,0 +1,222 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/session"
+	"github.com/Pairadux/muxly/internal/trust"
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// Event is a point in a session's life that hooks can run at.
+type Event string
+
+const (
+	OnCreate Event = "on_create"
+	OnAttach Event = "on_attach"
+	OnDetach Event = "on_detach"
+	OnKill   Event = "on_kill"
+)
+
+// LogFileName is the file in the muxly state directory that hook output is
+// appended to.
+const LogFileName = "hooks.log"
+
+// maxLogSize is the size past which the log is moved to LogFileName.1 and
+// started afresh.
+const maxLogSize = 1 << 20
+
+// Session is the session hooks run for.
+type Session struct {
+	Name string
+	Path string
+	// Template is the template the session was created from, if any.
+	Template string
+	// Muxly holds the hooks of the .muxly file in Path when the caller has
+	// already loaded it, and is empty when that file was missing, broken or
+	// not trusted. When nil, Commands reads the file itself.
+	Muxly *models.Hooks
+}
+
+// Commands returns the commands configured for event, in the order they
+// run: the global hook, then the template's, then the one in the session
+// directory's .muxly file. Unless s.Muxly is set, the .muxly file's hook
+// is read from the file and skipped with a warning unless the file is
+// trusted (see trust.Allowed).
+func Commands(cfg *models.Config, event Event, s Session) []string {
+	var commands []string
+	add := func(h models.Hooks) {
+		if command := forEvent(h, event); command != "" {
+			commands = append(commands, command)
+		}
+	}
+
+	add(cfg.Hooks)
+	if s.Template != "" {
+		if tmpl, found := config.FindTemplateByName(cfg, s.Template); found {
+			add(tmpl.Hooks)
+		}
+	}
+	if s.Muxly != nil {
+		add(*s.Muxly)
+	} else if s.Path != "" {
+		if command := muxlyFileCommand(cfg, event, s.Path); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// muxlyFileCommand returns the hook for event in the .muxly file in dir, if
+// the file is trusted to run it. A broken file has no hooks; it is reported
+// when sessions are created from it and by muxly doctor, not for every event.
+func muxlyFileCommand(cfg *models.Config, event Event, dir string) string {
+	path := filepath.Join(dir, session.MuxlyFileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	layout, err := session.ParseMuxlyFile(path, data)
+	if err != nil {
+		return ""
+	}
+	command := forEvent(layout.Hooks, event)
+	if command == "" {
+		return ""
+	}
+	if !trust.Allowed(cfg.Settings.MuxlyFilePolicy, path, data) {
+		fmt.Fprintf(os.Stderr, "Warning: skipping %s hook of untrusted %s (run 'muxly trust %s' to allow it)\n", event, path, dir)
+		return ""
+	}
+	return command
+}
+
+func forEvent(h models.Hooks, event Event) string {
+	switch event {
+	case OnCreate:
+		return h.OnCreate
+	case OnAttach:
+		return h.OnAttach
+	case OnDetach:
+		return h.OnDetach
+	case OnKill:
+		return h.OnKill
+	}
+	return ""
+}
+
+// Run runs the commands for event one after another with sh -c, in the
+// session directory and with the session described in MUXLY_SESSION,
+// MUXLY_PATH and MUXLY_TEMPLATE. Each command is stopped after
+// settings.hook_timeout. Output goes to the hook log; failures are reported
+// as warnings and never stop muxly.
+func Run(cfg *models.Config, event Event, s Session) {
+	commands := Commands(cfg, event, s)
+	if len(commands) == 0 {
+		return
+	}
+
+	timeout := cfg.Settings.HookTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultHookTimeout
+	}
+
+	for _, command := range commands {
+		start := time.Now()
+		output, err := run(command, s, timeout)
+		logPath, logErr := appendLog(event, s, command, output, err, time.Since(start))
+
+		if err != nil {
+			if logErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s hook for session %q failed: %v\n%s", event, s.Name, err, output)
+			} else {
+				fmt.Fprintf(os.Stderr, "Warning: %s hook for session %q failed: %v (output in %s)\n", event, s.Name, err, logPath)
+			}
+		} else if logErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write hook log: %v\n", logErr)
+		}
+	}
+}
+
+// run runs command and returns its combined output.
+func run(command string, s Session, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	if info, err := os.Stat(s.Path); err == nil && info.IsDir() {
+		cmd.Dir = s.Path
+	}
+	cmd.Env = append(os.Environ(),
+		constants.EnvHookSession+"="+s.Name,
+		constants.EnvHookPath+"="+s.Path,
+		constants.EnvHookTemplate+"="+s.Template,
+	)
+	// Background processes started by the hook may keep its output open;
+	// stop waiting for them shortly after the hook itself exits.
+	cmd.WaitDelay = time.Second
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	return output.Bytes(), err
+}
+
+// LogPath returns the hook log, $XDG_STATE_HOME/muxly/hooks.log.
+func LogPath() (string, error) {
+	dir, err := utility.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, LogFileName), nil
+}
+
+// appendLog records a finished hook in the hook log and returns the log's
+// path.
+func appendLog(event Event, s Session, command string, output []byte, runErr error, elapsed time.Duration) (string, error) {
+	path, err := LogPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), constants.DirectoryPermissions); err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		_ = os.Rename(path, path+".1")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, constants.FilePermissions)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	status := "ok"
+	if runErr != nil {
+		status = runErr.Error()
+	}
+	fmt.Fprintf(f, "%s %s %s (%s, %s): %s\n", time.Now().Format(time.RFC3339), event, s.Name, status, elapsed.Round(time.Millisecond), command)
+	if len(output) > 0 {
+		f.Write(output)
+		if output[len(output)-1] != '\n' {
+			f.WriteString("\n")
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,159 @@
+package hooks
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/trust"
+)
+
+func TestCommandsOrder(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	muxly := "hooks:\n  on_create: make up\n"
+	if err := os.WriteFile(filepath.Join(dir, ".muxly"), []byte(muxly), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &models.Config{
+		Hooks: models.Hooks{OnCreate: "global", OnKill: "global kill"},
+		Templates: []models.SessionTemplate{
+			{Name: "go", Hooks: models.Hooks{OnCreate: "template"}},
+		},
+		Settings: models.Settings{MuxlyFilePolicy: config.MuxlyFileAllow},
+	}
+
+	tests := []struct {
+		name    string
+		event   Event
+		session Session
+		want    []string
+	}{
+		{name: "all levels", event: OnCreate, session: Session{Name: "api", Path: dir, Template: "go"}, want: []string{"global", "template", "make up"}},
+		{name: "without template", event: OnCreate, session: Session{Name: "api", Path: dir}, want: []string{"global", "make up"}},
+		{name: "loaded .muxly file", event: OnCreate, session: Session{Name: "api", Path: dir, Muxly: &models.Hooks{OnCreate: "loaded"}}, want: []string{"global", "loaded"}},
+		{name: "ignored .muxly file", event: OnCreate, session: Session{Name: "api", Path: dir, Muxly: &models.Hooks{}}, want: []string{"global"}},
+		{name: "unknown template", event: OnCreate, session: Session{Name: "api", Template: "gone"}, want: []string{"global"}},
+		{name: "other event", event: OnKill, session: Session{Name: "api", Path: dir, Template: "go"}, want: []string{"global kill"}},
+		{name: "nothing configured", event: OnAttach, session: Session{Name: "api", Path: dir, Template: "go"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Commands(cfg, tt.event, tt.session); !slices.Equal(got, tt.want) {
+				t.Errorf("Commands() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandsSkipUntrustedMuxlyFile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".muxly")
+	data := []byte("hooks:\n  on_create: make up\n")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &models.Config{Settings: models.Settings{MuxlyFilePolicy: config.MuxlyFilePrompt}}
+	s := Session{Name: "api", Path: dir}
+
+	if got := Commands(cfg, OnCreate, s); len(got) != 0 {
+		t.Fatalf("Commands() = %q for an untrusted .muxly file, want none", got)
+	}
+
+	store, err := trust.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Trust(path, data, time.Now())
+	if err := store.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := Commands(cfg, OnCreate, s); !slices.Equal(got, []string{"make up"}) {
+		t.Errorf("Commands() = %q for a trusted .muxly file, want [make up]", got)
+	}
+}
+
+func TestCommandsBrokenMuxlyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".muxly"), []byte("windows: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &models.Config{Settings: models.Settings{MuxlyFilePolicy: config.MuxlyFileAllow}}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	got := Commands(cfg, OnAttach, Session{Name: "api", Path: dir})
+	os.Stderr = stderr
+	w.Close()
+	warnings, _ := io.ReadAll(r)
+
+	if len(got) != 0 {
+		t.Errorf("Commands() = %q for a broken .muxly file, want none", got)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Commands() warned %q, want no output", warnings)
+	}
+}
+
+func TestRunEnvironmentAndLog(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	cfg := &models.Config{Hooks: models.Hooks{
+		OnCreate: `echo "$MUXLY_SESSION|$MUXLY_PATH|$MUXLY_TEMPLATE|$(pwd)" > out; echo done`,
+	}}
+
+	Run(cfg, OnCreate, Session{Name: "api", Path: dir, Template: "go"})
+
+	out, err := os.ReadFile(filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("hook did not run in the session directory: %v", err)
+	}
+	if want := "api|" + dir + "|go|" + dir + "\n"; string(out) != want {
+		t.Errorf("hook saw %q, want %q", out, want)
+	}
+
+	logPath, err := LogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("reading hook log: %v", err)
+	}
+	if !strings.Contains(string(log), "on_create api (ok") || !strings.Contains(string(log), "done\n") {
+		t.Errorf("hook log = %q, want the event, status and output", log)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &models.Config{
+		Hooks:    models.Hooks{OnKill: "sleep 10"},
+		Settings: models.Settings{HookTimeout: 100 * time.Millisecond},
+	}
+
+	start := time.Now()
+	Run(cfg, OnKill, Session{Name: "api"})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Run() took %s, want it stopped after the timeout", elapsed)
+	}
+
+	logPath, _ := LogPath()
+	log, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(log), "timed out after 100ms") {
+		t.Errorf("hook log = %q, want a timeout", log)
+	}
+}
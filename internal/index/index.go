@@ -0,0 +1,203 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// FileName is the name of the index file inside the muxly cache directory.
+const FileName = "index.json"
+
+// formatVersion is bumped whenever the on-disk layout changes; indexes with a
+// different version are discarded on load.
+const formatVersion = 1
+
+// Record holds the cached result of scanning one scan_dir root.
+type Record struct {
+	Root         string           `json:"root"`
+	Depth        int              `json:"depth"`
+	Ignore       []string         `json:"ignore,omitempty"`
+	Markers      []string         `json:"markers,omitempty"`
+	StopAtMarker bool             `json:"stop_at_marker,omitempty"`
+	Dirs         []string         `json:"dirs"`
+	Mtimes       map[string]int64 `json:"mtimes"`
+	ScannedAt    time.Time        `json:"scanned_at"`
+}
+
+// Index is an on-disk cache of scan results, keyed by scan root, depth,
+// ignore set and project markers. Records are revalidated against directory modification times
+// before use, so a scan root is only walked again when something under it
+// changed.
+type Index struct {
+	path    string
+	dirty   bool
+	Version int                `json:"version"`
+	Records map[string]*Record `json:"records"`
+}
+
+// DefaultPath returns the location of the index file,
+// $XDG_CACHE_HOME/muxly/index.json.
+func DefaultPath() (string, error) {
+	dir, err := utility.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// New returns an empty index that will be saved to path.
+func New(path string) *Index {
+	return &Index{path: path, Version: formatVersion, Records: make(map[string]*Record)}
+}
+
+// Load reads the index at path. A missing, corrupt or outdated file yields an
+// empty index; only read errors are reported.
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return New(path), nil
+	}
+	if err != nil {
+		return New(path), fmt.Errorf("reading index: %w", err)
+	}
+
+	idx := New(path)
+	if err := json.Unmarshal(data, idx); err != nil || idx.Version != formatVersion || idx.Records == nil {
+		// A broken cache is never fatal, it is simply rebuilt.
+		return New(path), nil
+	}
+
+	return idx, nil
+}
+
+// LoadDefault reads the index from DefaultPath.
+func LoadDefault() (*Index, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return New(""), err
+	}
+	return Load(path)
+}
+
+// Path returns the file the index is saved to.
+func (i *Index) Path() string {
+	return i.path
+}
+
+// SubDirs returns the subdirectories of root for opts, serving the cached
+// record when it is still fresh and rescanning (and updating the cache)
+// otherwise.
+func (i *Index) SubDirs(root string, opts utility.ScanOptions) ([]string, error) {
+	key := recordKey(root, opts)
+	if rec, ok := i.Records[key]; ok && rec.Fresh() {
+		return rec.Dirs, nil
+	}
+
+	return i.Rescan(root, opts)
+}
+
+// Rescan walks root unconditionally and stores the result in the index.
+func (i *Index) Rescan(root string, opts utility.ScanOptions) ([]string, error) {
+	result, err := utility.ScanSubDirs(root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Record{
+		Root:         root,
+		Depth:        opts.MaxDepth,
+		Ignore:       sortedIgnoreNames(opts),
+		Markers:      opts.Markers,
+		StopAtMarker: opts.StopAtMarker,
+		Dirs:         result.Dirs,
+		Mtimes:       make(map[string]int64, len(result.Watch)),
+		ScannedAt:    time.Now(),
+	}
+	for _, dir := range result.Watch {
+		if info, err := os.Stat(dir); err == nil {
+			rec.Mtimes[dir] = info.ModTime().UnixNano()
+		}
+	}
+
+	i.Records[recordKey(root, opts)] = rec
+	i.dirty = true
+	return rec.Dirs, nil
+}
+
+// Fresh reports whether none of the watched directories changed since the
+// record was scanned.
+func (r *Record) Fresh() bool {
+	if len(r.Mtimes) == 0 {
+		return false
+	}
+	for dir, mtime := range r.Mtimes {
+		info, err := os.Stat(dir)
+		if err != nil || info.ModTime().UnixNano() != mtime {
+			return false
+		}
+	}
+	return true
+}
+
+// Sorted returns the records ordered by root and depth.
+func (i *Index) Sorted() []*Record {
+	records := make([]*Record, 0, len(i.Records))
+	for _, rec := range i.Records {
+		records = append(records, rec)
+	}
+	slices.SortFunc(records, func(a, b *Record) int {
+		if c := strings.Compare(a.Root, b.Root); c != 0 {
+			return c
+		}
+		return a.Depth - b.Depth
+	})
+	return records
+}
+
+// Save writes the index to disk if it changed since it was loaded.
+func (i *Index) Save() error {
+	if !i.dirty {
+		return nil
+	}
+	if i.path == "" {
+		return fmt.Errorf("index has no path")
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	if err := utility.WriteFileAtomic(i.path, data); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+
+	i.dirty = false
+	return nil
+}
+
+// recordKey identifies a scan by its root, depth, ignore set and markers.
+func recordKey(root string, opts utility.ScanOptions) string {
+	key := root + "|depth=" + strconv.Itoa(opts.MaxDepth) + "|ignore=" + strings.Join(sortedIgnoreNames(opts), ",")
+	if len(opts.Markers) > 0 {
+		key += "|markers=" + strings.Join(opts.Markers, ",") + "|stop=" + strconv.FormatBool(opts.StopAtMarker)
+	}
+	return key
+}
+
+func sortedIgnoreNames(opts utility.ScanOptions) []string {
+	names := make([]string, 0, len(opts.IgnoreDirNames))
+	for name := range opts.IgnoreDirNames {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,109 @@
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// touchDir bumps a directory's mtime so the change is visible regardless of
+// filesystem timestamp granularity.
+func touchDir(t *testing.T, dir string) {
+	t.Helper()
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dir, future, future); err != nil {
+		t.Fatalf("Chtimes(%q) unexpected error: %v", dir, err)
+	}
+}
+
+func TestSubDirsServesFreshRecord(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "project1", "src"), 0755)
+	os.MkdirAll(filepath.Join(root, "project2"), 0755)
+
+	opts := utility.ScanOptions{MaxDepth: 1}
+	idx := New(filepath.Join(t.TempDir(), FileName))
+
+	dirs, err := idx.SubDirs(root, opts)
+	if err != nil {
+		t.Fatalf("SubDirs() unexpected error: %v", err)
+	}
+	if len(dirs) != 2 {
+		t.Fatalf("SubDirs() returned %d dirs, want 2", len(dirs))
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	// Changes below the scanned depth do not invalidate the record.
+	os.MkdirAll(filepath.Join(root, "project1", "src", "deep"), 0755)
+	touchDir(t, filepath.Join(root, "project1", "src"))
+
+	loaded, err := Load(idx.Path())
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	rec := loaded.Records[recordKey(root, opts)]
+	if rec == nil || !rec.Fresh() {
+		t.Fatal("record should still be fresh after a change below the scanned depth")
+	}
+}
+
+func TestSubDirsRescansChangedRoot(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "project1"), 0755)
+
+	opts := utility.ScanOptions{MaxDepth: 2}
+	idx := New(filepath.Join(t.TempDir(), FileName))
+
+	if _, err := idx.SubDirs(root, opts); err != nil {
+		t.Fatalf("SubDirs() unexpected error: %v", err)
+	}
+
+	newDir := filepath.Join(root, "project1", "cmd")
+	os.MkdirAll(newDir, 0755)
+	touchDir(t, filepath.Join(root, "project1"))
+
+	if rec := idx.Records[recordKey(root, opts)]; rec.Fresh() {
+		t.Fatal("record should be stale after adding a directory within the scanned depth")
+	}
+
+	dirs, err := idx.SubDirs(root, opts)
+	if err != nil {
+		t.Fatalf("SubDirs() unexpected error: %v", err)
+	}
+	if !slices.Contains(dirs, newDir) {
+		t.Errorf("SubDirs() = %v, want it to contain %q", dirs, newDir)
+	}
+}
+
+func TestRecordKeySeparatesOptions(t *testing.T) {
+	a := recordKey("/dev", utility.ScanOptions{MaxDepth: 1})
+	b := recordKey("/dev", utility.ScanOptions{MaxDepth: 2})
+	c := recordKey("/dev", utility.ScanOptions{MaxDepth: 1, IgnoreDirNames: models.StringSet{"target": {}}})
+	d := recordKey("/dev", utility.ScanOptions{MaxDepth: 1, Markers: []string{".git"}})
+	e := recordKey("/dev", utility.ScanOptions{MaxDepth: 1, Markers: []string{".git"}, StopAtMarker: true})
+
+	keys := map[string]bool{a: true, b: true, c: true, d: true, e: true}
+	if len(keys) != 5 {
+		t.Errorf("recordKey() should differ by depth, ignore set and markers, got %q, %q, %q, %q, %q", a, b, c, d, e)
+	}
+}
+
+func TestLoadDiscardsCorruptIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	idx, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if len(idx.Records) != 0 {
+		t.Errorf("Load() of corrupt index returned %d records, want 0", len(idx.Records))
+	}
+}
@@ -1,17 +1,40 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // StringSet represents a set of strings using a map with empty struct values for memory efficiency
 type StringSet map[string]struct{}
 
+// Window describes a single tmux window. Cmd runs in the window's first pane;
+// Panes lists additional panes split off from it, and Layout optionally
+// arranges them with a named tmux layout or a raw layout string.
 type Window struct {
-	Name string `mapstructure:"name" yaml:"name"`
-	Cmd  string `mapstructure:"cmd,omitempty" yaml:"cmd,omitempty"`
+	Name   string `mapstructure:"name" yaml:"name"`
+	Path   string `mapstructure:"path,omitempty" yaml:"path,omitempty"`
+	Cmd    string `mapstructure:"cmd,omitempty" yaml:"cmd,omitempty"`
+	Layout string `mapstructure:"layout,omitempty" yaml:"layout,omitempty"`
+	Panes  []Pane `mapstructure:"panes,omitempty" yaml:"panes,omitempty"`
+}
+
+// Pane describes an additional pane split off a window.
+//
+// Split is "horizontal" (side by side) or "vertical" (stacked), defaulting to
+// vertical. Size is a percentage of the split window. Path is a working
+// directory relative to the session directory (absolute paths are used as-is).
+type Pane struct {
+	Split string `mapstructure:"split,omitempty" yaml:"split,omitempty"`
+	Size  int    `mapstructure:"size,omitempty" yaml:"size,omitempty"`
+	Path  string `mapstructure:"path,omitempty" yaml:"path,omitempty"`
+	Cmd   string `mapstructure:"cmd,omitempty" yaml:"cmd,omitempty"`
 }
 
 type SessionLayout struct {
 	Windows []Window `mapstructure:"windows" yaml:"windows"`
+	Hooks   Hooks    `mapstructure:"hooks,omitempty" yaml:"hooks,omitempty"`
 }
 
 type SessionTemplate struct {
@@ -20,13 +43,29 @@ type SessionTemplate struct {
 	Default bool     `mapstructure:"default,omitempty" yaml:"default,omitempty"`
 	Path    string   `mapstructure:"path,omitempty" yaml:"path,omitempty"`
 	Windows []Window `mapstructure:"windows" yaml:"windows"`
+	Hooks   Hooks    `mapstructure:"hooks,omitempty" yaml:"hooks,omitempty"`
 }
 
+// Hooks are shell commands run at points in a session's life: after muxly
+// creates it, when muxly attaches or switches to it, when muxly leaves it,
+// and after muxly kills it.
+type Hooks struct {
+	OnCreate string `mapstructure:"on_create,omitempty" yaml:"on_create,omitempty"`
+	OnAttach string `mapstructure:"on_attach,omitempty" yaml:"on_attach,omitempty"`
+	OnDetach string `mapstructure:"on_detach,omitempty" yaml:"on_detach,omitempty"`
+	OnKill   string `mapstructure:"on_kill,omitempty" yaml:"on_kill,omitempty"`
+}
+
+// ScanDir is a directory scanned for projects. When Markers is set, only
+// directories containing one of the marker files (e.g. .git, go.mod) are
+// listed, and StopAtMarker stops descending into a directory once it matches.
 type ScanDir struct {
-	Path     string `mapstructure:"path" yaml:"path"`
-	Depth    *int   `mapstructure:"depth,omitempty" yaml:"depth,omitempty"`
-	Alias    string `mapstructure:"alias,omitempty" yaml:"alias,omitempty"`
-	Template string `mapstructure:"template,omitempty" yaml:"template,omitempty"`
+	Path         string   `mapstructure:"path" yaml:"path"`
+	Depth        *int     `mapstructure:"depth,omitempty" yaml:"depth,omitempty"`
+	Alias        string   `mapstructure:"alias,omitempty" yaml:"alias,omitempty"`
+	Template     string   `mapstructure:"template,omitempty" yaml:"template,omitempty"`
+	Markers      []string `mapstructure:"markers,omitempty" yaml:"markers,omitempty"`
+	StopAtMarker bool     `mapstructure:"stop_at_marker,omitempty" yaml:"stop_at_marker,omitempty"`
 }
 
 type EntryDir struct {
@@ -38,6 +77,38 @@ type Session struct {
 	Name   string        `mapstructure:"name"`
 	Path   string        `mapstructure:"path"`
 	Layout SessionLayout `mapstructure:"layout"`
+	// Template names the template the layout came from, if any. It is kept
+	// on the tmux session so the template's hooks apply for its lifetime.
+	Template string `mapstructure:"template"`
+	// Group names a running session to share windows with, making the new
+	// session part of its tmux session group.
+	Group string `mapstructure:"group"`
+	// MuxlyHooks holds the hooks of the .muxly file in Path when it was
+	// already loaded for the layout, so the hooks do not read it again.
+	MuxlyHooks *Hooks `mapstructure:"-"`
+}
+
+// Workspace is a named group of sessions started and stopped together.
+type Workspace struct {
+	Name    string            `mapstructure:"name" yaml:"name"`
+	Members []WorkspaceMember `mapstructure:"members" yaml:"members"`
+	// Group links the members into one tmux session group, so they share
+	// their windows.
+	Group bool `mapstructure:"group,omitempty" yaml:"group,omitempty"`
+}
+
+// WorkspaceMember is one session of a workspace. Entry is a directory path
+// (containing "/" or starting with "~") or the session name of a directory
+// from scan_dirs or entry_dirs.
+type WorkspaceMember struct {
+	Entry    string `mapstructure:"entry" yaml:"entry"`
+	Template string `mapstructure:"template,omitempty" yaml:"template,omitempty"`
+}
+
+// IsPath reports whether the member's entry is a path rather than a session
+// name.
+func (m WorkspaceMember) IsPath() bool {
+	return strings.Contains(m.Entry, "/") || strings.HasPrefix(m.Entry, "~")
 }
 
 // GetDepth returns the depth for this scan directory, with fallback logic
@@ -66,6 +137,9 @@ func (s ScanDir) String() string {
 	if s.Template != "" {
 		result = fmt.Sprintf("%s (template: %s)", result, s.Template)
 	}
+	if len(s.Markers) > 0 {
+		result = fmt.Sprintf("%s (markers: %s)", result, strings.Join(s.Markers, ", "))
+	}
 	return result
 }
 
@@ -77,20 +151,53 @@ func (e EntryDir) String() string {
 	return result
 }
 
-// DirEntry holds metadata for a resolved directory in the selector
+// DirEntry holds metadata for a resolved directory in the selector.
+// SessionName is the tmux session name the entry opens, which may differ
+// from the label shown in the picker. Source is SourceScanDir or
+// SourceEntryDir for directories and empty for running tmux sessions.
 type DirEntry struct {
-	Path     string
-	Prefix   string
-	Template string
+	Path        string
+	Prefix      string
+	Template    string
+	SessionName string
+	Source      string
 }
 
+// Config sections a DirEntry can come from.
+const (
+	SourceScanDir  = "scan_dir"
+	SourceEntryDir = "entry_dir"
+)
+
 // Settings groups general configuration options
 type Settings struct {
-	Editor                  string `mapstructure:"editor" yaml:"editor"`
-	TmuxBase                int    `mapstructure:"tmux_base" yaml:"tmux_base"`
-	DefaultDepth            int    `mapstructure:"default_depth" yaml:"default_depth"`
-	TmuxSessionPrefix       string `mapstructure:"tmux_session_prefix" yaml:"tmux_session_prefix"`
-	AlwaysKillOnLastSession bool   `mapstructure:"always_kill_on_last_session" yaml:"always_kill_on_last_session"`
+	Editor                  string      `mapstructure:"editor" yaml:"editor"`
+	TmuxBase                int         `mapstructure:"tmux_base" yaml:"tmux_base"`
+	DefaultDepth            int         `mapstructure:"default_depth" yaml:"default_depth"`
+	TmuxSessionPrefix       string      `mapstructure:"tmux_session_prefix" yaml:"tmux_session_prefix"`
+	AlwaysKillOnLastSession bool        `mapstructure:"always_kill_on_last_session" yaml:"always_kill_on_last_session"`
+	SortOrder               string      `mapstructure:"sort_order" yaml:"sort_order"`
+	DisplayMode             string      `mapstructure:"display_mode" yaml:"display_mode"`
+	Picker                  string      `mapstructure:"picker" yaml:"picker"`
+	MuxlyFilePolicy         string      `mapstructure:"muxly_file_policy" yaml:"muxly_file_policy"`
+	TmuxSocketName          string      `mapstructure:"tmux_socket_name" yaml:"tmux_socket_name,omitempty"`
+	TmuxSocketPath          string      `mapstructure:"tmux_socket_path" yaml:"tmux_socket_path,omitempty"`
+	Fzf                     FzfSettings `mapstructure:"fzf" yaml:"fzf,omitempty"`
+	// HookTimeout limits how long each hook command may run.
+	HookTimeout time.Duration `mapstructure:"hook_timeout" yaml:"hook_timeout,omitempty"`
+}
+
+// FzfSettings customizes the fzf picker. Empty fields keep fzf's own
+// defaults. Args holds extra raw options, quoted as in a shell, and Tmux
+// enables fzf's --tmux popup mode with the given position and size.
+type FzfSettings struct {
+	Prompt string `mapstructure:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Height string `mapstructure:"height,omitempty" yaml:"height,omitempty"`
+	Layout string `mapstructure:"layout,omitempty" yaml:"layout,omitempty"`
+	Border string `mapstructure:"border,omitempty" yaml:"border,omitempty"`
+	Color  string `mapstructure:"color,omitempty" yaml:"color,omitempty"`
+	Args   string `mapstructure:"args,omitempty" yaml:"args,omitempty"`
+	Tmux   string `mapstructure:"tmux,omitempty" yaml:"tmux,omitempty"`
 }
 
 // Config represents the full configuration structure
@@ -99,5 +206,7 @@ type Config struct {
 	EntryDirs  []EntryDir        `mapstructure:"entry_dirs" yaml:"entry_dirs"`
 	IgnoreDirs []string          `mapstructure:"ignore_dirs" yaml:"ignore_dirs"`
 	Templates  []SessionTemplate `mapstructure:"templates" yaml:"templates"`
+	Hooks      Hooks             `mapstructure:"hooks" yaml:"hooks,omitempty"`
+	Workspaces []Workspace       `mapstructure:"workspaces" yaml:"workspaces,omitempty"`
 	Settings   Settings          `mapstructure:"settings" yaml:"settings"`
 }
@@ -128,6 +128,11 @@ func TestScanDirString(t *testing.T) {
 			scanDir:  ScanDir{Path: "~/Dev", Depth: intPtr(2), Alias: "dev", Template: "Go Dev"},
 			expected: "~/Dev:2 (alias: dev) (template: Go Dev)",
 		},
+		{
+			name:     "path with markers",
+			scanDir:  ScanDir{Path: "~/code", Depth: intPtr(5), Markers: []string{".git", "go.mod"}, StopAtMarker: true},
+			expected: "~/code:5 (markers: .git, go.mod)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,338 @@
+package picker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/fzf"
+	"github.com/Pairadux/muxly/internal/utility"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultPrompt is shown when Options.Prompt is empty, matching fzf.
+const DefaultPrompt = "> "
+
+// minPreviewWidth is the terminal width below which the preview is hidden.
+const minPreviewWidth = 60
+
+var (
+	cursorStyle    = lipgloss.NewStyle().Bold(true)
+	highlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Bold(true)
+	markerStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	dimStyle       = lipgloss.NewStyle().Faint(true)
+	borderStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// Builtin picks with an in-process fuzzy finder, for hosts without fzf. It
+// honors the prompt, layout, header, multi-select, expect keys and preview
+// of fzf.Options; the remaining options only apply to fzf.
+type Builtin struct{}
+
+// Select runs the built-in picker on the terminal.
+func (Builtin) Select(items []string, opts fzf.Options) (fzf.Result, error) {
+	program := tea.NewProgram(newModel(items, opts),
+		tea.WithAltScreen(),
+		tea.WithOutput(os.Stderr),
+		tea.WithInputTTY(),
+	)
+
+	final, err := program.Run()
+	if err != nil {
+		return fzf.Result{}, fmt.Errorf("running picker: %w", err)
+	}
+
+	m := final.(model)
+	if m.cancelled {
+		return fzf.Result{}, fmt.Errorf(constants.UserCancelledMsg)
+	}
+	return m.result, nil
+}
+
+// previewMsg carries the output of the preview command for an item.
+type previewMsg struct {
+	index  int
+	output string
+}
+
+type model struct {
+	items   []string
+	opts    fzf.Options
+	expect  map[string]string
+	input   textinput.Model
+	matches []Match
+	cursor  int
+	offset  int
+	marked  map[int]bool
+	preview map[int]string
+	width   int
+	height  int
+
+	result    fzf.Result
+	cancelled bool
+}
+
+func newModel(items []string, opts fzf.Options) model {
+	input := textinput.New()
+	input.Prompt = opts.Prompt
+	if input.Prompt == "" {
+		input.Prompt = DefaultPrompt
+	}
+	input.Focus()
+
+	// fzf names keys "ctrl-x" where bubbletea uses "ctrl+x".
+	expect := make(map[string]string, len(opts.Expect))
+	for _, key := range opts.Expect {
+		expect[strings.ReplaceAll(key, "-", "+")] = key
+	}
+
+	return model{
+		items:   items,
+		opts:    opts,
+		expect:  expect,
+		input:   input,
+		matches: Filter("", items),
+		marked:  make(map[int]bool),
+		preview: make(map[int]string),
+		width:   80,
+		height:  24,
+	}
+}
+
+func (m model) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.previewCmd())
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.scroll()
+		return m, nil
+
+	case previewMsg:
+		m.preview[msg.index] = msg.output
+		return m, nil
+
+	case tea.KeyMsg:
+		key := msg.String()
+		if fzfKey, ok := m.expect[key]; ok {
+			m.result = m.accept(fzfKey)
+			return m, tea.Quit
+		}
+
+		switch key {
+		case "ctrl+c", "ctrl+g", "ctrl+q", "esc":
+			m.cancelled = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.result = m.accept("")
+			return m, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			return m.move(-1)
+		case "down", "ctrl+n", "ctrl+j":
+			return m.move(1)
+		case "pgup":
+			return m.move(-m.listHeight())
+		case "pgdown":
+			return m.move(m.listHeight())
+		case "tab", "shift+tab":
+			if !m.opts.Multi || len(m.matches) == 0 {
+				return m, nil
+			}
+			index := m.matches[m.cursor].Index
+			m.marked[index] = !m.marked[index]
+			if key == "tab" {
+				return m.move(1)
+			}
+			return m.move(-1)
+		}
+
+		query := m.input.Value()
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		if m.input.Value() != query {
+			m.matches = Filter(m.input.Value(), m.items)
+			m.cursor, m.offset = 0, 0
+			return m, tea.Batch(cmd, m.previewCmd())
+		}
+		return m, cmd
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// move shifts the cursor by delta matches and requests a preview of the new
+// current item.
+func (m model) move(delta int) (tea.Model, tea.Cmd) {
+	if len(m.matches) == 0 {
+		return m, nil
+	}
+	m.cursor = max(0, min(len(m.matches)-1, m.cursor+delta))
+	m.scroll()
+	return m, m.previewCmd()
+}
+
+// scroll keeps the cursor inside the visible part of the list.
+func (m *model) scroll() {
+	height := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+}
+
+// accept builds the result for key: the marked items in their original
+// order, or the current item when nothing is marked.
+func (m model) accept(key string) fzf.Result {
+	result := fzf.Result{Key: key}
+	for i, item := range m.items {
+		if m.marked[i] {
+			result.Selections = append(result.Selections, item)
+		}
+	}
+	if len(result.Selections) == 0 && len(m.matches) > 0 {
+		result.Selections = []string{m.items[m.matches[m.cursor].Index]}
+	}
+	if len(result.Selections) > 0 {
+		result.Selection = result.Selections[0]
+	}
+	return result
+}
+
+// previewCmd runs the preview command for the current item in the
+// background, like fzf does, unless its output is already cached.
+func (m model) previewCmd() tea.Cmd {
+	if m.opts.Preview == "" || len(m.matches) == 0 {
+		return nil
+	}
+	index := m.matches[m.cursor].Index
+	if _, ok := m.preview[index]; ok {
+		return nil
+	}
+
+	command := strings.ReplaceAll(m.opts.Preview, "{}", utility.ShellQuote(m.items[index]))
+	return func() tea.Msg {
+		output, _ := exec.Command("sh", "-c", command).CombinedOutput()
+		return previewMsg{index: index, output: string(output)}
+	}
+}
+
+// headerLines returns the lines shown between the prompt and the list.
+func (m model) headerLines() []string {
+	lines := []string{dimStyle.Render(fmt.Sprintf("  %d/%d", len(m.matches), len(m.items)))}
+	if m.opts.Header != "" {
+		for _, line := range strings.Split(m.opts.Header, "\n") {
+			lines = append(lines, dimStyle.Render("  "+line))
+		}
+	}
+	return lines
+}
+
+func (m model) listHeight() int {
+	return max(1, m.height-1-len(m.headerLines()))
+}
+
+func (m model) showPreview() bool {
+	return m.opts.Preview != "" && m.width >= minPreviewWidth
+}
+
+func (m model) View() string {
+	listWidth := m.width
+	if m.showPreview() {
+		listWidth = m.width / 2
+	}
+
+	height := m.listHeight()
+	rows := make([]string, 0, height)
+	for i := m.offset; i < len(m.matches) && len(rows) < height; i++ {
+		rows = append(rows, m.renderRow(m.matches[i], i == m.cursor, listWidth))
+	}
+	for len(rows) < height {
+		rows = append(rows, "")
+	}
+
+	top := append([]string{m.input.View()}, m.headerLines()...)
+	var lines []string
+	if m.opts.Layout == "reverse" {
+		lines = append(top, rows...)
+	} else {
+		// fzf's default layout: prompt at the bottom, best match just above it.
+		slices.Reverse(rows)
+		slices.Reverse(top)
+		lines = append(rows, top...)
+	}
+
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, listWidth, "")
+	}
+	if !m.showPreview() {
+		return strings.Join(lines, "\n")
+	}
+
+	list := lipgloss.NewStyle().Width(listWidth).Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, m.renderPreview(m.width-listWidth))
+}
+
+// renderRow draws one match with its matched characters highlighted.
+func (m model) renderRow(match Match, current bool, width int) string {
+	pointer, marker := "  ", " "
+	if current {
+		pointer = cursorStyle.Render("> ")
+	}
+	if m.marked[match.Index] {
+		marker = markerStyle.Render("•")
+	}
+
+	var b strings.Builder
+	positions := match.Positions
+	for i, r := range []rune(m.items[match.Index]) {
+		if len(positions) > 0 && positions[0] == i {
+			b.WriteString(highlightStyle.Render(string(r)))
+			positions = positions[1:]
+			continue
+		}
+		if current {
+			b.WriteString(cursorStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return ansi.Truncate(pointer+marker+b.String(), width, "…")
+}
+
+// renderPreview draws the cached preview of the current item in a bordered
+// column of the given width.
+func (m model) renderPreview(width int) string {
+	var output string
+	if len(m.matches) > 0 {
+		output = m.preview[m.matches[m.cursor].Index]
+	}
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	rendered := make([]string, 0, m.height)
+	for i := 0; i < m.height; i++ {
+		line := ""
+		if i < len(lines) {
+			// Reset at the end so colors from captured panes do not bleed.
+			line = ansi.Truncate(strings.ReplaceAll(lines[i], "\t", "    "), width-2, "") + ansi.ResetStyle
+		}
+		rendered = append(rendered, borderStyle.Render("│ ")+line)
+	}
+	return strings.Join(rendered, "\n")
+}
@@ -0,0 +1,128 @@
+package picker
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/fzf"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// send feeds msgs to m in order and returns the resulting model along with
+// whether the last message made the picker quit.
+func send(t *testing.T, m model, msgs ...tea.Msg) (model, bool) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(model)
+	}
+	if cmd == nil {
+		return m, false
+	}
+	_, quit := cmd().(tea.QuitMsg)
+	return m, quit
+}
+
+func typeText(s string) tea.Msg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBuiltinEnterSelectsBestMatch(t *testing.T) {
+	items := []string{"dev/api", "dev/web", "work/website"}
+	m, quit := send(t, newModel(items, fzf.Options{}), typeText("web"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !quit {
+		t.Fatal("enter did not quit the picker")
+	}
+	want := fzf.Result{Selection: "dev/web", Selections: []string{"dev/web"}}
+	if m.result.Selection != want.Selection || !slices.Equal(m.result.Selections, want.Selections) || m.result.Key != "" {
+		t.Errorf("result = %+v, want %+v", m.result, want)
+	}
+}
+
+func TestBuiltinCancel(t *testing.T) {
+	for _, key := range []tea.KeyType{tea.KeyEsc, tea.KeyCtrlC} {
+		m, quit := send(t, newModel([]string{"a"}, fzf.Options{}), tea.KeyMsg{Type: key})
+		if !quit || !m.cancelled {
+			t.Errorf("%v: quit = %v, cancelled = %v; want both true", key, quit, m.cancelled)
+		}
+	}
+}
+
+func TestBuiltinEnterWithoutMatchesStaysOpen(t *testing.T) {
+	m, quit := send(t, newModel([]string{"api"}, fzf.Options{}), typeText("zzz"), tea.KeyMsg{Type: tea.KeyEnter})
+	if quit || m.cancelled {
+		t.Errorf("quit = %v, cancelled = %v; want the picker to stay open", quit, m.cancelled)
+	}
+}
+
+func TestBuiltinExpectKey(t *testing.T) {
+	opts := fzf.Options{Expect: []string{"ctrl-x"}}
+	m, quit := send(t, newModel([]string{"[TMUX] a", "b"}, opts),
+		tea.KeyMsg{Type: tea.KeyDown},
+		tea.KeyMsg{Type: tea.KeyCtrlX},
+	)
+
+	if !quit {
+		t.Fatal("expect key did not quit the picker")
+	}
+	if m.result.Key != "ctrl-x" || m.result.Selection != "b" {
+		t.Errorf("result = %+v, want key ctrl-x with selection b", m.result)
+	}
+}
+
+func TestBuiltinMultiSelect(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	m, _ := send(t, newModel(items, fzf.Options{Multi: true}),
+		tea.KeyMsg{Type: tea.KeyTab},
+		tea.KeyMsg{Type: tea.KeyDown},
+		tea.KeyMsg{Type: tea.KeyTab},
+		tea.KeyMsg{Type: tea.KeyEnter},
+	)
+
+	if want := []string{"a", "c"}; !slices.Equal(m.result.Selections, want) {
+		t.Errorf("Selections = %v, want %v", m.result.Selections, want)
+	}
+}
+
+func TestBuiltinTabIgnoredWithoutMulti(t *testing.T) {
+	m, _ := send(t, newModel([]string{"a", "b"}, fzf.Options{}),
+		tea.KeyMsg{Type: tea.KeyTab},
+		tea.KeyMsg{Type: tea.KeyEnter},
+	)
+
+	if want := []string{"a"}; !slices.Equal(m.result.Selections, want) {
+		t.Errorf("Selections = %v, want %v", m.result.Selections, want)
+	}
+}
+
+func TestBuiltinViewLayout(t *testing.T) {
+	items := []string{"first", "second"}
+	size := tea.WindowSizeMsg{Width: 40, Height: 6}
+
+	for _, tt := range []struct {
+		layout      string
+		promptFirst bool
+	}{
+		{layout: "", promptFirst: false},
+		{layout: "reverse", promptFirst: true},
+	} {
+		m, _ := send(t, newModel(items, fzf.Options{Prompt: "pick> ", Layout: tt.layout}), size)
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) != size.Height {
+			t.Fatalf("layout %q: view has %d lines, want %d", tt.layout, len(lines), size.Height)
+		}
+
+		promptLine := lines[len(lines)-1]
+		if tt.promptFirst {
+			promptLine = lines[0]
+		}
+		if !strings.Contains(promptLine, "pick> ") {
+			t.Errorf("layout %q: prompt not on expected line, view:\n%s", tt.layout, m.View())
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package picker
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Scoring weights for FuzzyMatch, loosely modelled on fzf's: matched characters
+// score a base amount, with bonuses for consecutive matches and matches at
+// word boundaries and penalties for gaps between matches.
+const (
+	scoreMatch          = 16
+	bonusConsecutive    = 8
+	bonusBoundary       = 8
+	bonusCamel          = 7
+	bonusFirstChar      = 8
+	penaltyGapStart     = 3
+	penaltyGapExtension = 1
+	boundaryDelimiter   = "/-_. "
+)
+
+// Match is the result of matching a query against an item. Index is the
+// item's position in the input to Filter; Positions are
+// the rune indices of the matched characters in the item.
+type Match struct {
+	Index     int
+	Score     int
+	Positions []int
+}
+
+// FuzzyMatch reports whether all runes of query appear in item in order and,
+// if so, how well they match. Matching is case-insensitive unless query
+// contains an uppercase letter (smart case). An empty query matches
+// everything with a score of 0.
+//
+// Each occurrence of the query's first rune is tried as a starting point:
+// a forward scan finds where the match ends, then a backward scan from there
+// tightens its start, like fzf's v1 algorithm. The best scoring span wins.
+func FuzzyMatch(query, item string) (Match, bool) {
+	q := []rune(query)
+	if len(q) == 0 {
+		return Match{}, true
+	}
+
+	caseSensitive := slices.ContainsFunc(q, unicode.IsUpper)
+	fold := func(r rune) rune {
+		if caseSensitive {
+			return r
+		}
+		return unicode.ToLower(r)
+	}
+	runes := []rune(item)
+	text := make([]rune, len(runes))
+	for i, r := range runes {
+		text[i] = fold(r)
+	}
+	for i := range q {
+		q[i] = fold(q[i])
+	}
+
+	best, found := Match{}, false
+	for from := range text {
+		if text[from] != q[0] {
+			continue
+		}
+		positions, ok := matchFrom(text, q, from)
+		if !ok {
+			break
+		}
+		m := Match{Score: score(runes, positions), Positions: positions}
+		if !found || m.Score > best.Score {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+// matchFrom finds the tightest match of q in text starting at or after from.
+func matchFrom(text, q []rune, from int) ([]int, bool) {
+	// Forward scan: find where the first full match ends.
+	qi, end := 0, -1
+	for i := from; i < len(text); i++ {
+		if text[i] == q[qi] {
+			qi++
+			if qi == len(q) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, false
+	}
+
+	// Backward scan: find the latest start that still matches.
+	qi = len(q) - 1
+	start := end
+	for i := end; i >= from; i-- {
+		if text[i] == q[qi] {
+			qi--
+			if qi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	// Collect positions greedily within [start, end].
+	positions := make([]int, 0, len(q))
+	qi = 0
+	for i := start; i <= end && qi < len(q); i++ {
+		if text[i] == q[qi] {
+			positions = append(positions, i)
+			qi++
+		}
+	}
+	return positions, true
+}
+
+// score rates the matched positions within text.
+func score(text []rune, positions []int) int {
+	total := 0
+	for n, pos := range positions {
+		total += scoreMatch
+
+		bonus := boundaryBonus(text, pos)
+		if n == 0 {
+			bonus *= 2
+			if pos == 0 {
+				bonus += bonusFirstChar
+			}
+		}
+
+		if n > 0 {
+			gap := pos - positions[n-1] - 1
+			if gap == 0 {
+				bonus = max(bonus, bonusConsecutive)
+			} else {
+				total -= penaltyGapStart + (gap-1)*penaltyGapExtension
+			}
+		}
+		total += bonus
+	}
+	return total
+}
+
+// boundaryBonus rewards matches at the start of a word: after a delimiter, at
+// a lower-to-upper case change, or at the start of the text.
+func boundaryBonus(text []rune, pos int) int {
+	if pos == 0 {
+		return bonusBoundary
+	}
+	prev, cur := text[pos-1], text[pos]
+	switch {
+	case strings.ContainsRune(boundaryDelimiter, prev):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && (unicode.IsLetter(cur) || unicode.IsDigit(cur)):
+		return bonusBoundary / 2
+	}
+	return 0
+}
+
+// Filter matches query against every item and returns the matches ranked by
+// score, then by item length, then by their original order. With an empty
+// query every item is returned in its original order.
+func Filter(query string, items []string) []Match {
+	matches := make([]Match, 0, len(items))
+	for i, item := range items {
+		if m, ok := FuzzyMatch(query, item); ok {
+			m.Index = i
+			matches = append(matches, m)
+		}
+	}
+	if query == "" {
+		return matches
+	}
+
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(len(items[a.Index]), len(items[b.Index]))
+	})
+	return matches
+}
@@ -0,0 +1,94 @@
+package picker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		item          string
+		wantOK        bool
+		wantPositions []int
+	}{
+		{name: "empty query", query: "", item: "anything", wantOK: true},
+		{name: "exact", query: "api", item: "api", wantOK: true, wantPositions: []int{0, 1, 2}},
+		{name: "subsequence", query: "mxl", item: "muxly", wantOK: true, wantPositions: []int{0, 2, 3}},
+		{name: "case insensitive", query: "dev", item: "DEV/src", wantOK: true, wantPositions: []int{0, 1, 2}},
+		{name: "smart case", query: "Dev", item: "dev/src", wantOK: false},
+		{name: "out of order", query: "ba", item: "ab", wantOK: false},
+		{name: "tightest span", query: "src", item: "scratch/src", wantOK: true, wantPositions: []int{8, 9, 10}},
+		{name: "unicode", query: "ü", item: "tüv", wantOK: true, wantPositions: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FuzzyMatch(tt.query, tt.item)
+			if ok != tt.wantOK {
+				t.Fatalf("FuzzyMatch(%q, %q) ok = %v, want %v", tt.query, tt.item, ok, tt.wantOK)
+			}
+			if ok && !slices.Equal(got.Positions, tt.wantPositions) {
+				t.Errorf("FuzzyMatch(%q, %q) positions = %v, want %v", tt.query, tt.item, got.Positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchScoring(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		better, worse string
+	}{
+		{name: "consecutive beats scattered", query: "web", better: "web-app", worse: "w-e-b"},
+		{name: "word boundary beats middle", query: "src", better: "dev/src", worse: "dev/xsrcx"},
+		{name: "prefix beats later match", query: "api", better: "api-server", worse: "my-api"},
+		{name: "camel case boundary", query: "ds", better: "DataStore", worse: "badsmell"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, ok := FuzzyMatch(tt.query, tt.better)
+			if !ok {
+				t.Fatalf("%q does not match %q", tt.query, tt.better)
+			}
+			worse, ok := FuzzyMatch(tt.query, tt.worse)
+			if !ok {
+				t.Fatalf("%q does not match %q", tt.query, tt.worse)
+			}
+			if better.Score <= worse.Score {
+				t.Errorf("score(%q) = %d, want more than score(%q) = %d", tt.better, better.Score, tt.worse, worse.Score)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	items := []string{"[TMUX] notes", "dev/web", "work/website", "api"}
+
+	t.Run("empty query keeps order", func(t *testing.T) {
+		got := Filter("", items)
+		if len(got) != len(items) {
+			t.Fatalf("Filter() returned %d matches, want %d", len(got), len(items))
+		}
+		for i, m := range got {
+			if m.Index != i {
+				t.Errorf("match %d has index %d, want %d", i, m.Index, i)
+			}
+		}
+	})
+
+	t.Run("ranks by score", func(t *testing.T) {
+		got := Filter("web", items)
+		var names []string
+		for _, m := range got {
+			names = append(names, items[m.Index])
+		}
+		want := []string{"dev/web", "work/website"}
+		if !slices.Equal(names, want) {
+			t.Errorf("Filter() = %v, want %v", names, want)
+		}
+	})
+}
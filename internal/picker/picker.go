@@ -0,0 +1,46 @@
+package picker
+
+import (
+	"os/exec"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/fzf"
+)
+
+// Picker presents a list of items and returns the user's selection. Every
+// implementation reports cancellation with an error whose message is
+// constants.UserCancelledMsg.
+type Picker interface {
+	Select(items []string, opts fzf.Options) (fzf.Result, error)
+}
+
+// Fzf picks with the external fzf binary.
+type Fzf struct{}
+
+// Select runs fzf.SelectWithFzf.
+func (Fzf) Select(items []string, opts fzf.Options) (fzf.Result, error) {
+	return fzf.SelectWithFzf(items, opts)
+}
+
+// New returns the picker for a settings.picker value. "auto" uses fzf when
+// it is on PATH and the built-in picker otherwise.
+func New(kind string) Picker {
+	if Resolve(kind) == config.PickerFzf {
+		return Fzf{}
+	}
+	return Builtin{}
+}
+
+// Resolve returns the picker a settings.picker value selects, either
+// config.PickerFzf or config.PickerBuiltin.
+func Resolve(kind string) string {
+	switch kind {
+	case config.PickerFzf, config.PickerBuiltin:
+		return kind
+	}
+
+	if _, err := exec.LookPath("fzf"); err == nil {
+		return config.PickerFzf
+	}
+	return config.PickerBuiltin
+}
@@ -0,0 +1,147 @@
+package preview
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+const (
+	// ReadmeLines is how many lines of a README are shown.
+	ReadmeLines = 20
+
+	// MaxListing caps the number of directory entries shown when a directory
+	// has no README.
+	MaxListing = 40
+)
+
+// readmeNames are checked in order when looking for a README to show.
+var readmeNames = []string{"README.md", "README", "README.txt", "README.rst", "readme.md"}
+
+// Session writes the window list of the named tmux session followed by a
+// capture of its active pane.
+func Session(w io.Writer, d tmux.Driver, name string) error {
+	windows, err := d.ListWindows(name)
+	if err != nil {
+		return fmt.Errorf("session %q: %w", name, err)
+	}
+
+	fmt.Fprintf(w, "Session: %s\n\n", name)
+	fmt.Fprint(w, windows)
+
+	pane, err := d.CapturePane(name)
+	if err != nil {
+		return nil
+	}
+	fmt.Fprintf(w, "\n%s\n", strings.Repeat("─", 40))
+	fmt.Fprint(w, strings.TrimRight(pane, "\n"))
+	fmt.Fprintln(w)
+
+	return nil
+}
+
+// Directory writes an overview of dir: its git branch and status when it is
+// a repository, then the head of its README, or a listing of its contents
+// when there is none.
+func Directory(w io.Writer, dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	fmt.Fprintf(w, "%s\n", dir)
+
+	if status := gitStatus(dir); status != "" {
+		fmt.Fprintf(w, "\n%s", status)
+	}
+
+	if readme, lines := readmeHead(dir, ReadmeLines); readme != "" {
+		fmt.Fprintf(w, "\n── %s ──\n", readme)
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+		}
+		return nil
+	}
+
+	names, more, err := listing(dir, MaxListing)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+	if more > 0 {
+		fmt.Fprintf(w, "… %d more\n", more)
+	}
+
+	return nil
+}
+
+// gitStatus returns the short branch and status of the repository at dir, or
+// an empty string when dir is not in a git work tree or git is unavailable.
+func gitStatus(dir string) string {
+	output, err := exec.Command("git", "-C", dir, "status", "--short", "--branch").Output()
+	if err != nil {
+		return ""
+	}
+
+	return string(output)
+}
+
+// readmeHead returns the name and first n lines of the README in dir.
+func readmeHead(dir string, n int) (string, []string) {
+	for _, name := range readmeNames {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+
+		var lines []string
+		scanner := bufio.NewScanner(f)
+		for len(lines) < n && scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.Close()
+
+		return name, lines
+	}
+
+	return "", nil
+}
+
+// listing returns up to limit entries of dir, directories first and marked
+// with a trailing slash, along with the number of entries left out.
+func listing(dir string, limit int) ([]string, int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var dirs, files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, entry.Name()+"/")
+		} else {
+			files = append(files, entry.Name())
+		}
+	}
+	slices.Sort(dirs)
+	slices.Sort(files)
+
+	names := append(dirs, files...)
+	if len(names) <= limit {
+		return names, 0, nil
+	}
+	return names[:limit], len(names) - limit, nil
+}
@@ -0,0 +1,84 @@
+package preview
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirectoryShowsReadmeHead(t *testing.T) {
+	dir := t.TempDir()
+	var readme strings.Builder
+	for i := 1; i <= ReadmeLines+5; i++ {
+		fmt.Fprintf(&readme, "line %d\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if err := Directory(&out, dir); err != nil {
+		t.Fatalf("Directory() error = %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "── README.md ──") {
+		t.Errorf("missing README header in:\n%s", got)
+	}
+	if !strings.Contains(got, fmt.Sprintf("line %d\n", ReadmeLines)) {
+		t.Errorf("missing line %d in:\n%s", ReadmeLines, got)
+	}
+	if strings.Contains(got, fmt.Sprintf("line %d\n", ReadmeLines+1)) {
+		t.Errorf("README not truncated to %d lines:\n%s", ReadmeLines, got)
+	}
+}
+
+func TestDirectoryListsContentsWithoutReadme(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"src", "docs"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"main.go", "go.mod"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	if err := Directory(&out, dir); err != nil {
+		t.Fatalf("Directory() error = %v", err)
+	}
+
+	want := dir + "\n\ndocs/\nsrc/\ngo.mod\nmain.go\n"
+	if got := out.String(); got != want {
+		t.Errorf("Directory() = %q, want %q", got, want)
+	}
+}
+
+func TestListingTruncates(t *testing.T) {
+	dir := t.TempDir()
+	for i := range 5 {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d", i)), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, more, err := listing(dir, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 3 || more != 2 {
+		t.Errorf("listing() = %v, %d more; want 3 names and 2 more", names, more)
+	}
+}
+
+func TestDirectoryMissing(t *testing.T) {
+	var out strings.Builder
+	if err := Directory(&out, filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Directory() on a missing path should fail")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/index"
 	"github.com/Pairadux/muxly/internal/models"
 	"github.com/Pairadux/muxly/internal/tmux"
 	"github.com/Pairadux/muxly/internal/utility"
@@ -14,17 +15,29 @@ import (
 // Builder constructs selector entries from configuration
 type Builder struct {
 	cfg     *models.Config
+	tmux    tmux.Driver
 	verbose bool
+	index   *index.Index
 }
 
-// NewBuilder creates a new Builder with the given configuration
-func NewBuilder(cfg *models.Config, verbose bool) *Builder {
+// NewBuilder creates a new Builder with the given configuration, looking up
+// tmux sessions through d
+func NewBuilder(cfg *models.Config, d tmux.Driver, verbose bool) *Builder {
 	return &Builder{
 		cfg:     cfg,
+		tmux:    d,
 		verbose: verbose,
 	}
 }
 
+// WithIndex makes the builder serve scan_dir results from the given project
+// index, rescanning only roots whose directories changed. The index is saved
+// at the end of BuildEntries. A nil index disables caching.
+func (b *Builder) WithIndex(idx *index.Index) *Builder {
+	b.index = idx
+	return b
+}
+
 // BuildEntries creates a map of display names to directory paths by
 // processing scan_dirs and entry_dirs from the configuration. It handles
 // directory scanning at specified depths, filters out ignored directories,
@@ -35,8 +48,8 @@ func NewBuilder(cfg *models.Config, verbose bool) *Builder {
 // Returns a map where keys are display names and values are resolved paths
 // or session names for existing tmux sessions.
 func (b *Builder) BuildEntries(flagDepth int) (map[string]models.DirEntry, error) {
-	existingSessions := tmux.GetTmuxSessionSet()
-	currentSession := tmux.GetCurrentTmuxSession()
+	existingSessions := tmux.GetTmuxSessionSet(b.tmux)
+	currentSession := b.tmux.CurrentSession()
 
 	ignorePaths, ignoreNames := b.buildIgnoreSets()
 	allPaths := b.collectAllPaths(flagDepth, ignorePaths, ignoreNames, currentSession)
@@ -45,9 +58,30 @@ func (b *Builder) BuildEntries(flagDepth int) (map[string]models.DirEntry, error
 	b.addDirectoryEntries(entries, allPaths, currentSession, existingSessions)
 	b.addTmuxSessionEntries(entries, existingSessions, currentSession)
 
+	if b.index != nil {
+		if err := b.index.Save(); err != nil && b.verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save project index: %v\n", err)
+		}
+	}
+
 	return entries, nil
 }
 
+// DirEntries returns every directory discovered through scan_dirs and
+// entry_dirs with its session name, whether or not a tmux session is
+// running for it. The flagDepth parameter overrides the scanning depth as in
+// BuildEntries.
+func (b *Builder) DirEntries(flagDepth int) []models.DirEntry {
+	ignorePaths, ignoreNames := b.buildIgnoreSets()
+	allPaths := b.collectAllPaths(flagDepth, ignorePaths, ignoreNames, b.tmux.CurrentSession())
+
+	sessionNames := DeduplicateDisplayNames(allPaths)
+	for i := range allPaths {
+		allPaths[i].SessionName = sessionNames[allPaths[i].Path]
+	}
+	return allPaths
+}
+
 // buildIgnoreSets partitions ignore directories into two sets for O(1) lookup:
 //   - ignorePaths: resolved absolute paths for entries that look like paths
 //     (contain "/" or start with "~"), e.g. "~/projects/archived"
@@ -88,12 +122,11 @@ func (b *Builder) buildIgnoreSets() (ignorePaths models.StringSet, ignoreNames m
 func (b *Builder) collectAllPaths(flagDepth int, ignorePaths, ignoreNames models.StringSet, currentSession string) []models.DirEntry {
 	var allPaths []models.DirEntry
 
-	addPath := func(path, prefix, template string) error {
-		if _, ignored := ignorePaths[path]; ignored {
+	addPath := func(entry models.DirEntry) error {
+		if _, ignored := ignorePaths[entry.Path]; ignored {
 			return nil
 		}
 
-		entry := models.DirEntry{Path: path, Prefix: prefix, Template: template}
 		allPaths = append(allPaths, entry)
 		return nil
 	}
@@ -113,24 +146,33 @@ func (b *Builder) collectAllPaths(flagDepth int, ignorePaths, ignoreNames models
 			}
 			continue
 		}
-		addPath(resolved, "", entryDir.Template)
+		addPath(models.DirEntry{Path: resolved, Template: entryDir.Template, Source: models.SourceEntryDir})
 	}
 
 	return allPaths
 }
 
 // addDirectoryEntries populates the entries map with display names for directories.
+// Entries are keyed by their picker label (see DisplayLabels) and carry the
+// deduplicated session name separately.
 func (b *Builder) addDirectoryEntries(entries map[string]models.DirEntry, allPaths []models.DirEntry, currentSession string, existingSessions map[string]bool) {
-	displayNames := DeduplicateDisplayNames(allPaths)
+	sessionNames := DeduplicateDisplayNames(allPaths)
+
+	var home string
+	if b.cfg.Settings.DisplayMode == config.DisplayHome {
+		home, _ = os.UserHomeDir()
+	}
+	labels := DisplayLabels(allPaths, sessionNames, b.cfg.Settings.DisplayMode, home)
 
 	for _, info := range allPaths {
-		displayName := displayNames[info.Path]
+		sessionName := sessionNames[info.Path]
 
-		if shouldSkipEntry(displayName, currentSession, existingSessions) {
+		if shouldSkipEntry(sessionName, currentSession, existingSessions) {
 			continue
 		}
 
-		entries[displayName] = info
+		info.SessionName = sessionName
+		entries[labels[info.Path]] = info
 	}
 }
 
@@ -142,12 +184,12 @@ func (b *Builder) addTmuxSessionEntries(entries map[string]models.DirEntry, exis
 		}
 
 		displayName := b.cfg.Settings.TmuxSessionPrefix + sessionName
-		entries[displayName] = models.DirEntry{Path: sessionName}
+		entries[displayName] = models.DirEntry{Path: sessionName, SessionName: sessionName}
 	}
 }
 
 // processScanDir scans a single scan_dir entry and adds all discovered subdirectories.
-func (b *Builder) processScanDir(scanDir models.ScanDir, flagDepth int, prefix string, ignoreNames models.StringSet, addEntry func(string, string, string) error) error {
+func (b *Builder) processScanDir(scanDir models.ScanDir, flagDepth int, prefix string, ignoreNames models.StringSet, addEntry func(models.DirEntry) error) error {
 	defaultDepth := b.cfg.Settings.DefaultDepth
 	effectiveDepth := scanDir.GetDepth(flagDepth, defaultDepth)
 
@@ -159,7 +201,12 @@ func (b *Builder) processScanDir(scanDir models.ScanDir, flagDepth int, prefix s
 		return nil
 	}
 
-	subDirs, err := utility.GetSubDirs(effectiveDepth, resolved, ignoreNames)
+	subDirs, err := b.scanSubDirs(resolved, utility.ScanOptions{
+		MaxDepth:       effectiveDepth,
+		IgnoreDirNames: ignoreNames,
+		Markers:        scanDir.Markers,
+		StopAtMarker:   scanDir.StopAtMarker,
+	})
 	if err != nil {
 		if b.verbose {
 			fmt.Fprintf(os.Stderr, "Warning: failed to scan directory %s: %v\n", resolved, err)
@@ -168,7 +215,8 @@ func (b *Builder) processScanDir(scanDir models.ScanDir, flagDepth int, prefix s
 	}
 
 	for _, subDir := range subDirs {
-		if err := addEntry(subDir, prefix, scanDir.Template); err != nil {
+		entry := models.DirEntry{Path: subDir, Prefix: prefix, Template: scanDir.Template, Source: models.SourceScanDir}
+		if err := addEntry(entry); err != nil {
 			return err
 		}
 	}
@@ -176,7 +224,17 @@ func (b *Builder) processScanDir(scanDir models.ScanDir, flagDepth int, prefix s
 	return nil
 }
 
+// scanSubDirs lists the subdirectories of a resolved scan root, going through
+// the project index when one is configured.
+func (b *Builder) scanSubDirs(root string, opts utility.ScanOptions) ([]string, error) {
+	if b.index == nil {
+		result, err := utility.ScanSubDirs(root, opts)
+		return result.Dirs, err
+	}
+	return b.index.SubDirs(root, opts)
+}
+
 // shouldSkipEntry determines if a directory entry should be excluded from the selector.
-func shouldSkipEntry(displayName, currentSession string, existingSessions map[string]bool) bool {
-	return displayName == currentSession || existingSessions[displayName]
+func shouldSkipEntry(sessionName, currentSession string, existingSessions map[string]bool) bool {
+	return sessionName == currentSession || existingSessions[sessionName]
 }
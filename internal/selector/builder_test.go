@@ -0,0 +1,53 @@
+package selector
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestBuildEntriesWithSessions(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "web", "docs"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &models.Config{
+		ScanDirs: []models.ScanDir{{Path: root}},
+		Settings: models.Settings{
+			DefaultDepth:      1,
+			TmuxSessionPrefix: "[TMUX] ",
+			DisplayMode:       config.DisplaySuffix,
+		},
+	}
+	// "api" is the current session and "web" runs in the background, so
+	// only "docs" is offered as a directory.
+	driver := tmux.NewFakeDriver("api", "web", "notes")
+	driver.Current = "api"
+
+	entries, err := NewBuilder(cfg, driver, false).BuildEntries(0)
+	if err != nil {
+		t.Fatalf("BuildEntries() error = %v", err)
+	}
+
+	var got []string
+	for label := range entries {
+		got = append(got, label)
+	}
+	slices.Sort(got)
+
+	want := []string{"[TMUX] notes", "[TMUX] web", "docs"}
+	if !slices.Equal(got, want) {
+		t.Errorf("BuildEntries() labels = %v, want %v", got, want)
+	}
+	if entry := entries["[TMUX] web"]; entry.SessionName != "web" {
+		t.Errorf("session entry = %+v, want session name web", entry)
+	}
+}
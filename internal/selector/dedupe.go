@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Pairadux/muxly/internal/config"
 	"github.com/Pairadux/muxly/internal/models"
 )
 
@@ -42,6 +43,62 @@ func DeduplicateDisplayNames(allPaths []models.DirEntry) map[string]string {
 	return result
 }
 
+// DisplayLabels returns the picker label for each path according to the
+// display mode. sessionNames holds the session names from
+// DeduplicateDisplayNames, which are always unique and used as-is in
+// "suffix" mode (the default).
+//
+// Precedence: the display mode picks the label; any label shared by more than
+// one path falls back to that path's session name so labels stay unique.
+//
+//   - suffix:   shortest unique path suffix, e.g. "work/src"
+//   - basename: directory name with its scan_dir alias, e.g. "dev/src"
+//   - home:     path relative to home, e.g. "~/Work/src"
+//   - absolute: full resolved path, e.g. "/home/user/Work/src"
+func DisplayLabels(allPaths []models.DirEntry, sessionNames map[string]string, mode, home string) map[string]string {
+	labels := make(map[string]string, len(allPaths))
+	counts := make(map[string]int, len(allPaths))
+
+	for _, info := range allPaths {
+		if _, seen := labels[info.Path]; seen {
+			continue
+		}
+		label := displayLabel(info, sessionNames[info.Path], mode, home)
+		labels[info.Path] = label
+		counts[label]++
+	}
+
+	for path, label := range labels {
+		if counts[label] > 1 {
+			labels[path] = sessionNames[path]
+		}
+	}
+
+	return labels
+}
+
+func displayLabel(info models.DirEntry, sessionName, mode, home string) string {
+	switch mode {
+	case config.DisplayBasename:
+		sanitizedName, dotCount := SanitizeSessionName(filepath.Base(info.Path))
+		return ApplyPrefix(info.Prefix, sanitizedName) + DotdirSuffix(dotCount)
+	case config.DisplayHome:
+		if home != "" {
+			if rel, err := filepath.Rel(home, info.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				if rel == "." {
+					return "~"
+				}
+				return "~" + string(filepath.Separator) + rel
+			}
+		}
+		return info.Path
+	case config.DisplayAbsolute:
+		return info.Path
+	default:
+		return sessionName
+	}
+}
+
 func buildDedupeEntries(allPaths []models.DirEntry) []dedupeEntry {
 	entries := make([]dedupeEntry, len(allPaths))
 	for i, info := range allPaths {
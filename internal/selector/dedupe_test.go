@@ -228,6 +228,108 @@ func TestDeduplicateDisplayNames(t *testing.T) {
 	}
 }
 
+func TestDisplayLabels(t *testing.T) {
+	const home = "/home/user"
+
+	input := []models.DirEntry{
+		{Path: "/home/user/Dev/muxly"},
+		{Path: "/home/user/Dev/src", Prefix: "dev"},
+		{Path: "/home/user/Work/src", Prefix: "work"},
+		{Path: "/home/user/.config"},
+		{Path: "/srv/app:v2"},
+		{Path: "/home/user"},
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		expected map[string]string
+	}{
+		{
+			name: "suffix uses session names",
+			mode: "suffix",
+			expected: map[string]string{
+				"/home/user/Dev/muxly": "muxly",
+				"/home/user/Dev/src":   "dev/src",
+				"/home/user/Work/src":  "work/src",
+				"/home/user/.config":   "config [dotdir]",
+				"/srv/app:v2":          "app-v2",
+				"/home/user":           "user",
+			},
+		},
+		{
+			name: "empty mode behaves like suffix",
+			mode: "",
+			expected: map[string]string{
+				"/home/user/Dev/muxly": "muxly",
+				"/home/user/Dev/src":   "dev/src",
+			},
+		},
+		{
+			name: "basename always applies alias",
+			mode: "basename",
+			expected: map[string]string{
+				"/home/user/Dev/muxly": "muxly",
+				"/home/user/Dev/src":   "dev/src",
+				"/home/user/Work/src":  "work/src",
+				"/home/user/.config":   "config [dotdir]",
+				"/srv/app:v2":          "app-v2",
+			},
+		},
+		{
+			name: "home relative with absolute fallback",
+			mode: "home",
+			expected: map[string]string{
+				"/home/user/Dev/muxly": "~/Dev/muxly",
+				"/home/user/Work/src":  "~/Work/src",
+				"/home/user/.config":   "~/.config",
+				"/srv/app:v2":          "/srv/app:v2",
+				"/home/user":           "~",
+			},
+		},
+		{
+			name: "absolute paths",
+			mode: "absolute",
+			expected: map[string]string{
+				"/home/user/Dev/muxly": "/home/user/Dev/muxly",
+				"/srv/app:v2":          "/srv/app:v2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DisplayLabels(input, DeduplicateDisplayNames(input), tt.mode, home)
+			for path, expectedLabel := range tt.expected {
+				if got[path] != expectedLabel {
+					t.Errorf("DisplayLabels()[%q] = %q, want %q", path, got[path], expectedLabel)
+				}
+			}
+		})
+	}
+}
+
+func TestDisplayLabelsCollisionFallsBackToSessionName(t *testing.T) {
+	input := []models.DirEntry{
+		{Path: "/Dev/api"},
+		{Path: "/Work/api"},
+		{Path: "/Dev/web"},
+	}
+
+	got := DisplayLabels(input, DeduplicateDisplayNames(input), "basename", "")
+
+	expected := map[string]string{
+		"/Dev/api":  "Dev/api",
+		"/Work/api": "Work/api",
+		"/Dev/web":  "web",
+	}
+	for path, expectedLabel := range expected {
+		if got[path] != expectedLabel {
+			t.Errorf("DisplayLabels()[%q] = %q, want %q", path, got[path], expectedLabel)
+		}
+	}
+}
+
 func TestGetPathSuffix(t *testing.T) {
 	tests := []struct {
 		name     string
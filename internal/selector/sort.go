@@ -0,0 +1,59 @@
+package selector
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/history"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+// SortEntryNames orders picker entries according to the configured sort order.
+//
+// Alphabetical ordering lists active tmux sessions first, then everything else
+// case-insensitively. Recent and frecency ordering rank entries by the history
+// store (keyed by the entry's session name) and fall back to alphabetical
+// ordering for ties and entries never opened. A nil store behaves like an
+// empty one.
+func SortEntryNames(names []string, entries map[string]models.DirEntry, tmuxPrefix, order string, hist *history.Store) {
+	now := time.Now()
+	sessionName := func(name string) string {
+		if entry, ok := entries[name]; ok && entry.SessionName != "" {
+			return entry.SessionName
+		}
+		return strings.TrimPrefix(name, tmuxPrefix)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if hist != nil {
+			switch order {
+			case config.SortRecent:
+				if c := hist.LastUsed(sessionName(b)).Compare(hist.LastUsed(sessionName(a))); c != 0 {
+					return c
+				}
+			case config.SortFrecency:
+				if c := cmp.Compare(hist.Score(sessionName(b), now), hist.Score(sessionName(a), now)); c != 0 {
+					return c
+				}
+			}
+		}
+		return compareAlphabetical(a, b, tmuxPrefix)
+	})
+}
+
+// compareAlphabetical lists active tmux sessions first, then compares names
+// case-insensitively.
+func compareAlphabetical(a, b, tmuxPrefix string) int {
+	isTmuxA := strings.HasPrefix(a, tmuxPrefix)
+	isTmuxB := strings.HasPrefix(b, tmuxPrefix)
+	if isTmuxA && !isTmuxB {
+		return -1
+	}
+	if !isTmuxA && isTmuxB {
+		return 1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,67 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/history"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+func TestSortEntryNames(t *testing.T) {
+	now := time.Now()
+	hist := &history.Store{Entries: map[string]history.Entry{
+		"daily":  {Count: 20, LastUsed: now.Add(-2 * time.Hour)},
+		"recent": {Count: 1, LastUsed: now.Add(-time.Minute)},
+		"old":    {Count: 50, LastUsed: now.Add(-90 * 24 * time.Hour)},
+	}}
+
+	input := []string{"Zeta", "~/Dev/old", "[TMUX] recent", "alpha", "daily", "[TMUX] other"}
+	entries := map[string]models.DirEntry{
+		"~/Dev/old": {Path: "/home/user/Dev/old", SessionName: "old"},
+	}
+
+	tests := []struct {
+		name     string
+		order    string
+		hist     *history.Store
+		expected []string
+	}{
+		{
+			name:     "alphabetical puts tmux sessions first",
+			order:    config.SortAlphabetical,
+			hist:     hist,
+			expected: []string{"[TMUX] other", "[TMUX] recent", "alpha", "daily", "Zeta", "~/Dev/old"},
+		},
+		{
+			name:     "recent ranks by last use",
+			order:    config.SortRecent,
+			hist:     hist,
+			expected: []string{"[TMUX] recent", "daily", "~/Dev/old", "[TMUX] other", "alpha", "Zeta"},
+		},
+		{
+			name:     "frecency ranks by weighted count",
+			order:    config.SortFrecency,
+			hist:     hist,
+			expected: []string{"daily", "~/Dev/old", "[TMUX] recent", "[TMUX] other", "alpha", "Zeta"},
+		},
+		{
+			name:     "nil history falls back to alphabetical",
+			order:    config.SortFrecency,
+			hist:     nil,
+			expected: []string{"[TMUX] other", "[TMUX] recent", "alpha", "daily", "Zeta", "~/Dev/old"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := append([]string(nil), input...)
+			SortEntryNames(names, entries, config.DefaultTmuxSessionPrefix, tt.order, tt.hist)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("SortEntryNames() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
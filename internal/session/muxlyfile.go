@@ -1,32 +1,165 @@
 package session
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/constants"
 	"github.com/Pairadux/muxly/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
-// LoadMuxlyFile attempts to load a .muxly file from the given directory.
-//
-// Returns the parsed SessionLayout if the file exists and is valid YAML,
-// or an empty SessionLayout otherwise. This provides project-specific
-// session configuration that overrides the global session_layout from config.
+// MuxlyFileName is the name of the per-directory layout file.
+const MuxlyFileName = ".muxly"
+
+// LoadMuxlyFile loads the .muxly file in dir, which provides a
+// project-specific layout overriding the templates from the config.
 //
-// Errors are silently ignored since .muxly files are optional overrides.
-func LoadMuxlyFile(path string) models.SessionLayout {
-	layoutPath := filepath.Join(path, ".muxly")
+// A missing file is not an error: it returns an empty SessionLayout. A file
+// that cannot be parsed returns a *ParseError.
+func LoadMuxlyFile(dir string) (models.SessionLayout, error) {
+	layoutPath := filepath.Join(dir, MuxlyFileName)
 
 	data, err := os.ReadFile(layoutPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return models.SessionLayout{}, nil
+	}
 	if err != nil {
-		return models.SessionLayout{}
+		return models.SessionLayout{}, fmt.Errorf("reading %s: %w", layoutPath, err)
 	}
 
+	return ParseMuxlyFile(layoutPath, data)
+}
+
+// ParseError reports why a .muxly file could not be parsed or is invalid.
+type ParseError struct {
+	Path     string
+	Problems []Problem
+}
+
+// Problem is a single mistake in a .muxly file. Line is 0 when yaml.v3 does
+// not know where the problem is.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Problems) == 1 {
+		return fmt.Sprintf("%s: %s", e.Path, e.Problems[0])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d problems", e.Path, len(e.Problems))
+	for _, p := range e.Problems {
+		b.WriteString("\n  " + p.String())
+	}
+	return b.String()
+}
+
+// yamlLine matches the position yaml.v3 puts in front of its messages.
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+
+// ParseMuxlyFile parses the contents of the .muxly file at path. Unknown
+// keys are rejected, so a misspelt key is reported instead of ignored, and
+// panes and layouts are validated as in templates.
+func ParseMuxlyFile(path string, data []byte) (models.SessionLayout, error) {
 	var layout models.SessionLayout
-	if err := yaml.Unmarshal(data, &layout); err != nil {
-		return models.SessionLayout{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(&layout)
+	if err == nil || errors.Is(err, io.EOF) {
+		if err := config.ValidateWindows(layout.Windows); err != nil {
+			return models.SessionLayout{}, &ParseError{Path: path, Problems: []Problem{{Message: err.Error()}}}
+		}
+		return layout, nil
+	}
+
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	parseErr := &ParseError{Path: path}
+	for _, msg := range messages {
+		parseErr.Problems = append(parseErr.Problems, newProblem(msg))
+	}
+	return models.SessionLayout{}, parseErr
+}
+
+func newProblem(msg string) Problem {
+	var p Problem
+	if m := yamlLine.FindStringSubmatch(msg); m != nil {
+		p.Line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	// "field wndows not found in type models.SessionLayout" names a Go type
+	// the user never sees.
+	if field, _, ok := strings.Cut(msg, " not found in type models."); ok {
+		msg = "unknown key " + strings.TrimPrefix(field, "field ")
+	}
+	p.Message = msg
+	return p
+}
+
+// Commands lists every command the layout would run, in window commands,
+// pane commands and hooks, each labelled with where it comes from.
+func Commands(layout models.SessionLayout) []string {
+	var commands []string
+	for _, w := range layout.Windows {
+		if w.Cmd != "" {
+			commands = append(commands, fmt.Sprintf("window %s: %s", w.Name, w.Cmd))
+		}
+		for i, p := range w.Panes {
+			if p.Cmd != "" {
+				commands = append(commands, fmt.Sprintf("window %s, pane %d: %s", w.Name, i+2, p.Cmd))
+			}
+		}
+	}
+
+	for _, hook := range []struct{ name, cmd string }{
+		{"on_create", layout.Hooks.OnCreate},
+		{"on_attach", layout.Hooks.OnAttach},
+		{"on_detach", layout.Hooks.OnDetach},
+		{"on_kill", layout.Hooks.OnKill},
+	} {
+		if hook.cmd != "" {
+			commands = append(commands, fmt.Sprintf("%s hook: %s", hook.name, hook.cmd))
+		}
+	}
+	return commands
+}
+
+// WriteMuxlyFile writes layout to the .muxly file in dir, replacing any
+// existing one, and returns the file's path.
+func WriteMuxlyFile(dir string, layout models.SessionLayout) (string, error) {
+	data, err := yaml.Marshal(layout)
+	if err != nil {
+		return "", fmt.Errorf("encoding layout: %w", err)
+	}
+
+	layoutPath := filepath.Join(dir, MuxlyFileName)
+	if err := os.WriteFile(layoutPath, data, constants.FilePermissions); err != nil {
+		return "", fmt.Errorf("writing %s: %w", layoutPath, err)
 	}
 
-	return layout
+	return layoutPath, nil
 }
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseMuxlyFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		contents     string
+		wantWindows  int
+		wantProblems []Problem
+	}{
+		{name: "valid", contents: "windows:\n  - name: editor\n    cmd: nvim\n", wantWindows: 1},
+		{name: "empty", contents: ""},
+		{
+			name:         "unknown top-level key",
+			contents:     "windows:\n  - name: editor\nwndows: []\n",
+			wantProblems: []Problem{{Line: 3, Message: "unknown key wndows"}},
+		},
+		{
+			name:     "unknown keys in windows and panes",
+			contents: "windows:\n  - name: editor\n    comand: nvim\n    panes:\n      - splt: horizontal\n",
+			wantProblems: []Problem{
+				{Line: 3, Message: "unknown key comand"},
+				{Line: 5, Message: "unknown key splt"},
+			},
+		},
+		{
+			name:         "syntax error",
+			contents:     "windows:\n  - name: editor\n    cmd: nvim: x\n",
+			wantProblems: []Problem{{Line: 3, Message: "mapping values are not allowed in this context"}},
+		},
+		{
+			name:         "wrong type",
+			contents:     "windows: editor\n",
+			wantProblems: []Problem{{Line: 1, Message: "cannot unmarshal !!str `editor` into []models.Window"}},
+		},
+		{
+			name:         "invalid split",
+			contents:     "windows:\n  - name: editor\n    panes:\n      - split: horiz\n",
+			wantProblems: []Problem{{Message: `window "editor" pane 1 has invalid split "horiz" (use horizontal or vertical)`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := ParseMuxlyFile("/p/.muxly", []byte(tt.contents))
+
+			var parseErr *ParseError
+			if tt.wantProblems == nil {
+				if err != nil {
+					t.Fatalf("ParseMuxlyFile() error = %v", err)
+				}
+			} else if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseMuxlyFile() error = %v, want a *ParseError", err)
+			} else {
+				if parseErr.Path != "/p/.muxly" {
+					t.Errorf("Path = %q, want /p/.muxly", parseErr.Path)
+				}
+				if !slices.Equal(parseErr.Problems, tt.wantProblems) {
+					t.Errorf("Problems = %+v, want %+v", parseErr.Problems, tt.wantProblems)
+				}
+			}
+			if len(layout.Windows) != tt.wantWindows {
+				t.Errorf("windows = %+v, want %d", layout.Windows, tt.wantWindows)
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	single := &ParseError{Path: "/p/.muxly", Problems: []Problem{{Line: 3, Message: "unknown key wndows"}}}
+	if got, want := single.Error(), "/p/.muxly: line 3: unknown key wndows"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	several := &ParseError{Path: "/p/.muxly", Problems: []Problem{{Line: 3, Message: "a"}, {Message: "b"}}}
+	if got, want := several.Error(), "/p/.muxly: 2 problems\n  line 3: a\n  b"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestLoadMuxlyFileMissing(t *testing.T) {
+	layout, err := LoadMuxlyFile(t.TempDir())
+	if err != nil || len(layout.Windows) != 0 {
+		t.Errorf("LoadMuxlyFile() = %+v, %v for a directory without .muxly, want an empty layout", layout, err)
+	}
+}
+
+func TestLoadMuxlyFileUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	// A directory named .muxly exists but cannot be read as a file.
+	if err := os.Mkdir(filepath.Join(dir, MuxlyFileName), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadMuxlyFile(dir); err == nil {
+		t.Error("LoadMuxlyFile() succeeded for an unreadable .muxly")
+	}
+}
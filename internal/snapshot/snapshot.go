@@ -0,0 +1,253 @@
+package snapshot
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// DirName is the directory inside the muxly state directory holding one
+// snapshot file per session.
+const DirName = "snapshots"
+
+const fileExt = ".json"
+
+// shells are programs that are not restarted on restore, since every pane
+// starts one anyway.
+var shells = []string{"bash", "zsh", "fish", "sh", "dash", "ksh", "tcsh", "csh", "nu", "elvish", "xonsh"}
+
+// Snapshot is the saved state of one tmux session, kept so the session can be
+// rebuilt after a reboot or kill-server.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Path    string    `json:"path"`
+	SavedAt time.Time `json:"saved_at"`
+	Windows []Window  `json:"windows"`
+	// Template is the template the session was created from, if any.
+	Template string `json:"template,omitempty"`
+}
+
+// Window is a saved window. Layout is tmux's raw layout string, which puts
+// the panes back in their exact arrangement.
+type Window struct {
+	Name   string `json:"name"`
+	Layout string `json:"layout"`
+	Panes  []Pane `json:"panes"`
+}
+
+// Pane is a saved pane: its working directory and the command line of the
+// program running in it, empty when that is a shell.
+type Pane struct {
+	Path    string `json:"path"`
+	Command string `json:"command,omitempty"`
+}
+
+// Capture reads the current state of the named session through d.
+func Capture(d tmux.Driver, name string) (Snapshot, error) {
+	path, err := d.SessionPath(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	panes, err := d.ListPanes(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	tmpl, err := d.SessionOption(name, tmux.TemplateOption)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snap := Snapshot{Name: name, Path: path, SavedAt: time.Now(), Template: tmpl}
+	for i, p := range panes {
+		if i == 0 || p.WindowIndex != panes[i-1].WindowIndex {
+			snap.Windows = append(snap.Windows, Window{
+				Name:   p.WindowName,
+				Layout: p.WindowLayout,
+			})
+		}
+		w := &snap.Windows[len(snap.Windows)-1]
+		pane := Pane{Path: p.Path}
+		if !IsShell(p.Command) {
+			pane.Command = cmp.Or(p.CommandLine, p.Command)
+		}
+		w.Panes = append(w.Panes, pane)
+	}
+	if len(snap.Windows) == 0 {
+		return Snapshot{}, fmt.Errorf("session %q has no windows", name)
+	}
+
+	return snap, nil
+}
+
+// Session turns the snapshot into a session for tmux.CreateSession. Each
+// window starts in its first pane's directory and splits off the others,
+// then gets its saved layout back. Programs other than shells are started
+// again in their panes when withCommands is set.
+func (s Snapshot) Session(withCommands bool) models.Session {
+	return models.Session{
+		Name:     s.Name,
+		Path:     s.Path,
+		Layout:   models.SessionLayout{Windows: s.windows(withCommands, func(path string) string { return path })},
+		Template: s.Template,
+	}
+}
+
+// Layout turns the snapshot into a reusable layout, as used by templates and
+// .muxly files. It matches Session, except that directories inside the
+// session directory are made relative to it so the layout fits other
+// projects too.
+func (s Snapshot) Layout() models.SessionLayout {
+	return models.SessionLayout{Windows: s.windows(true, func(path string) string {
+		return relativeTo(s.Path, path)
+	})}
+}
+
+func (s Snapshot) windows(withCommands bool, dir func(string) string) []models.Window {
+	command := func(p Pane) string {
+		if !withCommands || IsShell(p.Command) {
+			return ""
+		}
+		return p.Command
+	}
+
+	var windows []models.Window
+	for _, w := range s.Windows {
+		if len(w.Panes) == 0 {
+			continue
+		}
+
+		first := w.Panes[0]
+		window := models.Window{Name: w.Name, Path: dir(first.Path), Cmd: command(first)}
+		for _, p := range w.Panes[1:] {
+			window.Panes = append(window.Panes, models.Pane{Path: dir(p.Path), Cmd: command(p)})
+		}
+		// A single pane has nothing to arrange.
+		if len(w.Panes) > 1 {
+			window.Layout = w.Layout
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// relativeTo returns path relative to root, "" for root itself, or path
+// unchanged when it lies outside root.
+func relativeTo(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		return path
+	}
+	if rel == "." {
+		return ""
+	}
+	return rel
+}
+
+// IsShell reports whether command is a shell, including the user's $SHELL.
+func IsShell(command string) bool {
+	if command == "" || slices.Contains(shells, command) {
+		return true
+	}
+	return command == filepath.Base(os.Getenv(constants.EnvShell))
+}
+
+// Dir returns the snapshot directory, $XDG_STATE_HOME/muxly/snapshots.
+func Dir() (string, error) {
+	dir, err := utility.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// FilePath returns the file the named session's snapshot is stored in.
+// Session names are escaped, so any name maps to a single file.
+func FilePath(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, url.PathEscape(name)+fileExt), nil
+}
+
+// Save writes the snapshot, replacing any earlier one of the same session,
+// and returns the file it was written to.
+func Save(s Snapshot) (string, error) {
+	path, err := FilePath(s.Name)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := utility.WriteFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("writing snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads the snapshot of the named session.
+func Load(name string) (Snapshot, error) {
+	path, err := FilePath(name)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{}, fmt.Errorf("no snapshot of session %q", name)
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("reading snapshot: %w", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("parsing snapshot %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// List returns the names of all saved sessions, sorted.
+func List() ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshots: %w", err)
+	}
+
+	var names []string
+	for _, f := range files {
+		escaped, ok := strings.CutSuffix(f.Name(), fileExt)
+		if !ok || f.IsDir() {
+			continue
+		}
+		if name, err := url.PathUnescape(escaped); err == nil {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
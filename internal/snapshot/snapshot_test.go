@@ -0,0 +1,157 @@
+package snapshot
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/models"
+	"github.com/Pairadux/muxly/internal/tmux"
+)
+
+func TestCaptureAndRestore(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+
+	live := models.Session{
+		Name: "api",
+		Path: "/code/api",
+		Layout: models.SessionLayout{Windows: []models.Window{
+			{Name: "editor", Cmd: "nvim"},
+			{Name: "run", Cmd: "zsh", Layout: "b6c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []models.Pane{
+				{Path: "logs", Cmd: "tail -f app.log"},
+			}},
+		}},
+	}
+	d := &tmux.FakeDriver{}
+	if err := d.CreateSession(live); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := Capture(d, "api")
+	if err != nil {
+		t.Fatalf("Capture() error = %v", err)
+	}
+
+	wantWindows := []Window{
+		{Name: "editor", Panes: []Pane{{Path: "/code/api", Command: "nvim"}}},
+		{Name: "run", Layout: "b6c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []Pane{
+			{Path: "/code/api"},
+			{Path: "/code/api/logs", Command: "tail -f app.log"},
+		}},
+	}
+	if snap.Name != "api" || snap.Path != "/code/api" || !reflect.DeepEqual(snap.Windows, wantWindows) {
+		t.Fatalf("Capture() = %+v, want windows %+v", snap, wantWindows)
+	}
+
+	tests := []struct {
+		name         string
+		withCommands bool
+		want         []models.Window
+	}{
+		{
+			name:         "with commands",
+			withCommands: true,
+			want: []models.Window{
+				{Name: "editor", Path: "/code/api", Cmd: "nvim"},
+				{Name: "run", Path: "/code/api", Layout: "b6c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []models.Pane{
+					{Path: "/code/api/logs", Cmd: "tail -f app.log"},
+				}},
+			},
+		},
+		{
+			name: "without commands",
+			want: []models.Window{
+				{Name: "editor", Path: "/code/api"},
+				{Name: "run", Path: "/code/api", Layout: "b6c1,80x24,0,0{40x24,0,0,1,39x24,41,0,2}", Panes: []models.Pane{
+					{Path: "/code/api/logs"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := snap.Session(tt.withCommands)
+			if session.Name != "api" || session.Path != "/code/api" {
+				t.Errorf("Session() = %s at %s, want api at /code/api", session.Name, session.Path)
+			}
+			if !reflect.DeepEqual(session.Layout.Windows, tt.want) {
+				t.Errorf("Session() windows = %+v, want %+v", session.Layout.Windows, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureMissingSession(t *testing.T) {
+	if _, err := Capture(tmux.NewFakeDriver("api"), "web"); err == nil {
+		t.Fatal("Capture() succeeded for a missing session")
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	for _, name := range []string{"web", "api", "odd/name"} {
+		snap := Snapshot{Name: name, Path: "/code", Windows: []Window{{Name: "main", Panes: []Pane{{Path: "/code"}}}}}
+		if _, err := Save(snap); err != nil {
+			t.Fatalf("Save(%q) error = %v", name, err)
+		}
+	}
+
+	names, err := List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if want := []string{"api", "odd/name", "web"}; !slices.Equal(names, want) {
+		t.Errorf("List() = %v, want %v", names, want)
+	}
+
+	snap, err := Load("odd/name")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if snap.Name != "odd/name" || len(snap.Windows) != 1 {
+		t.Errorf("Load() = %+v", snap)
+	}
+
+	if _, err := Load("missing"); err == nil {
+		t.Error("Load() succeeded for a missing snapshot")
+	}
+}
+
+func TestListWithoutSnapshots(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	names, err := List()
+	if err != nil || len(names) != 0 {
+		t.Errorf("List() = %v, %v; want no snapshots", names, err)
+	}
+}
+
+func TestLayoutRelativePaths(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+
+	snap := Snapshot{
+		Name: "api",
+		Path: "/code/api",
+		Windows: []Window{
+			{Name: "editor", Layout: "b25d,80x24,0,0,1", Panes: []Pane{{Path: "/code/api", Command: "nvim"}}},
+			{Name: "run", Layout: "c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}", Panes: []Pane{
+				{Path: "/code/api/cmd/server", Command: "zsh"},
+				{Path: "/var/log", Command: "tail"},
+			}},
+			{Name: "sibling", Panes: []Pane{{Path: "/code/api-docs", Command: "bash"}}},
+		},
+	}
+
+	want := []models.Window{
+		{Name: "editor", Cmd: "nvim"},
+		{Name: "run", Path: "cmd/server", Layout: "c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}", Panes: []models.Pane{
+			{Path: "/var/log", Cmd: "tail"},
+		}},
+		{Name: "sibling", Path: "/code/api-docs"},
+	}
+	if got := snap.Layout().Windows; !reflect.DeepEqual(got, want) {
+		t.Errorf("Layout() windows = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,484 @@
+package tmux
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+// Driver performs the tmux operations muxly builds on. ExecDriver talks to a
+// real tmux server; FakeDriver keeps everything in memory so command flows
+// can be tested without one.
+//
+// Session names passed to a Driver are matched exactly, never as prefixes.
+type Driver interface {
+	// ListSessions returns the names of all sessions. It returns no sessions
+	// and no error when no server is running.
+	ListSessions() ([]string, error)
+	HasSession(name string) bool
+	// ListSessionInfo returns the details of all sessions, in the order of
+	// ListSessions.
+	ListSessionInfo() ([]SessionInfo, error)
+	// CurrentSession returns the session of the client muxly runs in, or ""
+	// outside tmux.
+	CurrentSession() string
+	// InsideTmux reports whether muxly runs inside a tmux client, and so
+	// must switch that client rather than attach a new one.
+	InsideTmux() bool
+	ServerRunning() bool
+	// BaseIndex returns the server's global base-index option, the number
+	// new windows start counting at.
+	BaseIndex() (int, error)
+
+	// CreateSession builds the session's windows and panes without
+	// attaching to it.
+	CreateSession(session models.Session) error
+	// SwitchClient points the current client at target.
+	SwitchClient(target string) error
+	// AttachSession attaches the terminal to target, blocking until the
+	// client detaches. attached is called once the client has attached, while
+	// it still is; it is not called when the client never attached.
+	AttachSession(target string, attached func()) error
+	KillSession(name string) error
+	KillServer() error
+	RenameSession(old, new string) error
+
+	SessionPath(name string) (string, error)
+	// SessionOption returns the value of a session option such as
+	// TemplateOption, or "" when it is not set.
+	SessionOption(name, option string) (string, error)
+	SetSessionOption(name, option, value string) error
+	// ListPanes returns every pane of the session, ordered by window and
+	// then by pane index.
+	ListPanes(name string) ([]PaneInfo, error)
+	// ListWindows returns one line per window of the session, giving its
+	// index, name and pane count and marking the active window.
+	ListWindows(name string) (string, error)
+	// CapturePane returns the visible contents of the session's active pane,
+	// including color escape sequences.
+	CapturePane(name string) (string, error)
+	SetBuffer(text string) error
+}
+
+// PaneInfo describes a pane of a running session along with its window.
+type PaneInfo struct {
+	WindowIndex  int
+	WindowName   string
+	WindowLayout string
+	// Path is the pane's current working directory.
+	Path string
+	// Command is the name of the program running in the pane, e.g. "nvim"
+	// or the shell.
+	Command string
+	// CommandLine is that program with its arguments, e.g. "npm run dev",
+	// or "" when it cannot be read.
+	CommandLine string
+}
+
+// SessionInfo describes a running session.
+type SessionInfo struct {
+	Name string
+	Path string
+	// Windows is the number of windows in the session.
+	Windows int
+	// Attached is the number of clients attached to the session.
+	Attached int
+}
+
+// ExecDriver runs the tmux binary for every operation. It talks to the
+// default server unless SocketName (tmux -L) or SocketPath (tmux -S) is set.
+type ExecDriver struct {
+	SocketName string
+	SocketPath string
+}
+
+var _ Driver = ExecDriver{}
+
+func (d ExecDriver) command(args ...string) *exec.Cmd {
+	return exec.Command("tmux", append(d.socketArgs(), args...)...)
+}
+
+// socketArgs returns the tmux flags selecting the driver's server.
+func (d ExecDriver) socketArgs() []string {
+	switch {
+	case d.SocketPath != "":
+		return []string{"-S", d.SocketPath}
+	case d.SocketName != "":
+		return []string{"-L", d.SocketName}
+	}
+	return nil
+}
+
+// socket returns the path of the server's socket the way tmux derives it,
+// or "" for the default server.
+func (d ExecDriver) socket() string {
+	if d.SocketPath != "" {
+		if abs, err := filepath.Abs(d.SocketPath); err == nil {
+			return abs
+		}
+		return d.SocketPath
+	}
+	if d.SocketName == "" {
+		return ""
+	}
+
+	dir := os.Getenv(constants.EnvTmuxTmpdir)
+	if dir == "" {
+		dir = "/tmp"
+	}
+	return filepath.Join(dir, fmt.Sprintf("tmux-%d", os.Getuid()), d.SocketName)
+}
+
+func (d ExecDriver) ListSessions() ([]string, error) {
+	output, err := d.command("list-sessions", "-F", "#{session_name}").Output()
+	if err != nil {
+		if !d.ServerRunning() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	// Parse session names from output, one per line
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	sessions := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line != "" {
+			sessions = append(sessions, line)
+		}
+	}
+
+	return sessions, nil
+}
+
+// sessionFormat lists the SessionInfo fields for list-sessions -F.
+var sessionFormat = strings.Join([]string{
+	"#{session_name}", "#{session_path}", "#{session_windows}", "#{session_attached}",
+}, fieldSeparator)
+
+func (d ExecDriver) ListSessionInfo() ([]SessionInfo, error) {
+	output, err := d.command("list-sessions", "-F", sessionFormat).Output()
+	if err != nil {
+		if !d.ServerRunning() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	return parseSessions(string(output))
+}
+
+// parseSessions parses list-sessions output in sessionFormat.
+func parseSessions(output string) ([]SessionInfo, error) {
+	var sessions []SessionInfo
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, fieldSeparator)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected list-sessions output %q", line)
+		}
+		windows, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected window count %q", fields[2])
+		}
+		attached, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected client count %q", fields[3])
+		}
+		sessions = append(sessions, SessionInfo{
+			Name:     fields[0],
+			Path:     fields[1],
+			Windows:  windows,
+			Attached: attached,
+		})
+	}
+	return sessions, nil
+}
+
+func (d ExecDriver) HasSession(name string) bool {
+	return d.command("has-session", "-t", "="+name).Run() == nil
+}
+
+func (d ExecDriver) CurrentSession() string {
+	if !d.InsideTmux() {
+		return ""
+	}
+
+	output, err := d.command("display-message", "-p", "#{session_name}").Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(output))
+}
+
+// InsideTmux reports whether muxly runs in a client of the driver's server.
+// A client of another server does not count, since it cannot be switched to
+// this server's sessions.
+func (d ExecDriver) InsideTmux() bool {
+	env := os.Getenv(constants.EnvTmux)
+	if env == "" {
+		return false
+	}
+
+	socket := d.socket()
+	if socket == "" {
+		return true
+	}
+	// $TMUX is "socket,pid,session index".
+	clientSocket, _, _ := strings.Cut(env, ",")
+	return clientSocket == socket
+}
+
+func (d ExecDriver) ServerRunning() bool {
+	return d.command("list-sessions").Run() == nil
+}
+
+func (d ExecDriver) BaseIndex() (int, error) {
+	output, err := d.command("show-options", "-gv", "base-index").Output()
+	if err != nil {
+		return 0, fmt.Errorf("reading base-index: %w", err)
+	}
+
+	base, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("parsing base-index %q: %w", strings.TrimSpace(string(output)), err)
+	}
+	return base, nil
+}
+
+// CreateSession builds the whole session in a single tmux invocation (see
+// buildSessionArgs), so it appears at once however many windows it has. tmux
+// stops at the first failing command; the partly built session is then
+// killed rather than left behind.
+func (d ExecDriver) CreateSession(session models.Session) error {
+	// Checked up front so the cleanup below can never kill a session that
+	// existed before.
+	if d.HasSession(session.Name) {
+		return fmt.Errorf("session %q already exists", session.Name)
+	}
+
+	var stderr bytes.Buffer
+	cmd := d.command(buildSessionArgs(session)...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if d.HasSession(session.Name) {
+			if killErr := d.KillSession(session.Name); killErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to clean up session %q: %v\n", session.Name, killErr)
+			}
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (d ExecDriver) SwitchClient(target string) error {
+	return d.command("switch-client", "-t", target).Run()
+}
+
+// attachPollInterval is how often AttachSession checks whether its client
+// has attached.
+const attachPollInterval = 50 * time.Millisecond
+
+func (d ExecDriver) AttachSession(target string, attached func()) error {
+	cmd := d.command("attach-session", "-t", target)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	// Inside a client of another server, tmux refuses to attach unless $TMUX
+	// is cleared; the new client then runs nested in the current pane.
+	if os.Getenv(constants.EnvTmux) != "" {
+		cmd.Env = slices.DeleteFunc(os.Environ(), func(kv string) bool {
+			return strings.HasPrefix(kv, constants.EnvTmux+"=")
+		})
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	pid := strconv.Itoa(cmd.Process.Pid)
+	ticker := time.NewTicker(attachPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			// A client that detached before it was seen still attached.
+			if err == nil {
+				attached()
+			}
+			return err
+		case <-ticker.C:
+			if d.clientAttached(pid) {
+				attached()
+				return <-done
+			}
+		}
+	}
+}
+
+// clientAttached reports whether the tmux client with process id pid is
+// attached to the server.
+func (d ExecDriver) clientAttached(pid string) bool {
+	output, err := d.command("list-clients", "-F", "#{client_pid}").Output()
+	if err != nil {
+		return false
+	}
+	return slices.Contains(strings.Fields(string(output)), pid)
+}
+
+func (d ExecDriver) KillSession(name string) error {
+	if err := d.command("kill-session", "-t", "="+name).Run(); err != nil {
+		return fmt.Errorf("killing session: %w", err)
+	}
+
+	return nil
+}
+
+func (d ExecDriver) KillServer() error {
+	if err := d.command("kill-server").Run(); err != nil {
+		return fmt.Errorf("killing server: %w", err)
+	}
+
+	return nil
+}
+
+func (d ExecDriver) RenameSession(old, new string) error {
+	if err := d.command("rename-session", "-t", "="+old, new).Run(); err != nil {
+		return fmt.Errorf("renaming session: %w", err)
+	}
+
+	return nil
+}
+
+func (d ExecDriver) SessionPath(name string) (string, error) {
+	output, err := d.command("display-message", "-p", "-t", "="+name+":", "#{session_path}").Output()
+	if err != nil {
+		return "", fmt.Errorf("reading session path: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (d ExecDriver) SessionOption(name, option string) (string, error) {
+	output, err := d.command("show-options", "-qv", "-t", "="+name+":", option).Output()
+	if err != nil {
+		return "", fmt.Errorf("reading session option %s: %w", option, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+func (d ExecDriver) SetSessionOption(name, option, value string) error {
+	if err := d.command("set-option", "-t", "="+name+":", option, value).Run(); err != nil {
+		return fmt.Errorf("setting session option %s: %w", option, err)
+	}
+
+	return nil
+}
+
+// fieldSeparator separates the fields of paneFormat and sessionFormat. tmux
+// replaces control characters such as tabs in format output, so a printable
+// sequence that will not occur in names or paths is used instead.
+const fieldSeparator = "::muxly::"
+
+// paneFormat lists the PaneInfo fields for list-panes -F.
+var paneFormat = strings.Join([]string{
+	"#{window_index}", "#{window_name}", "#{window_layout}", "#{pane_current_path}", "#{pane_current_command}", "#{pane_pid}",
+}, fieldSeparator)
+
+func (d ExecDriver) ListPanes(name string) ([]PaneInfo, error) {
+	output, err := d.command("list-panes", "-s", "-t", "="+name, "-F", paneFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing panes: %w", err)
+	}
+
+	panes, pids, err := parsePanes(string(output))
+	if err != nil {
+		return nil, err
+	}
+	for i := range panes {
+		panes[i].CommandLine = foregroundCommandLine(pids[i])
+	}
+	return panes, nil
+}
+
+// parsePanes parses list-panes output in paneFormat, returning the pid of
+// each pane's first process alongside it.
+func parsePanes(output string) ([]PaneInfo, []int, error) {
+	var (
+		panes []PaneInfo
+		pids  []int
+	)
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, fieldSeparator)
+		if len(fields) != 6 {
+			return nil, nil, fmt.Errorf("unexpected list-panes output %q", line)
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, nil, fmt.Errorf("unexpected window index %q", fields[0])
+		}
+		pid, err := strconv.Atoi(fields[5])
+		if err != nil {
+			return nil, nil, fmt.Errorf("unexpected pane pid %q", fields[5])
+		}
+		panes = append(panes, PaneInfo{
+			WindowIndex:  index,
+			WindowName:   fields[1],
+			WindowLayout: fields[2],
+			Path:         fields[3],
+			Command:      fields[4],
+		})
+		pids = append(pids, pid)
+	}
+	return panes, pids, nil
+}
+
+func (d ExecDriver) ListWindows(name string) (string, error) {
+	format := "#{window_index}: #{window_name} (#{window_panes} panes)#{?window_active, *,}"
+	output, err := d.command("list-windows", "-t", "="+name, "-F", format).Output()
+	if err != nil {
+		return "", fmt.Errorf("listing windows: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (d ExecDriver) CapturePane(name string) (string, error) {
+	output, err := d.command("capture-pane", "-p", "-e", "-t", "="+name+":").Output()
+	if err != nil {
+		return "", fmt.Errorf("capturing pane: %w", err)
+	}
+
+	return string(output), nil
+}
+
+func (d ExecDriver) SetBuffer(text string) error {
+	if err := d.command("set-buffer", "--", text).Run(); err != nil {
+		return fmt.Errorf("setting buffer: %w", err)
+	}
+
+	return nil
+}
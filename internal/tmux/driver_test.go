@@ -0,0 +1,332 @@
+package tmux
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/history"
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+func testSession(name string) models.Session {
+	return models.Session{
+		Name: name,
+		Path: "/home/user/" + name,
+		Layout: models.SessionLayout{Windows: []models.Window{
+			{Name: "editor", Cmd: "nvim"},
+			{Name: "shell", Panes: []models.Pane{{Split: "horizontal"}}},
+		}},
+	}
+}
+
+func TestCreateAndSwitchSession(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &models.Config{Settings: models.Settings{TmuxBase: 1}}
+
+	tests := []struct {
+		name         string
+		driver       *FakeDriver
+		session      models.Session
+		wantErr      error
+		wantSwitches []string
+		wantCurrent  string
+		wantSessions []string
+	}{
+		{
+			name:         "inside tmux creates and switches",
+			driver:       &FakeDriver{Sessions: []FakeSession{{Name: "main"}}, Current: "main"},
+			session:      testSession("api"),
+			wantSwitches: []string{"api:1"},
+			wantCurrent:  "api",
+			wantSessions: []string{"main", "api"},
+		},
+		{
+			name:         "existing session is only switched to",
+			driver:       &FakeDriver{Sessions: []FakeSession{{Name: "main"}, {Name: "api"}}, Current: "main"},
+			session:      testSession("api"),
+			wantSwitches: []string{"api:1"},
+			wantCurrent:  "api",
+			wantSessions: []string{"main", "api"},
+		},
+		{
+			name:         "outside tmux attaches",
+			driver:       &FakeDriver{},
+			session:      testSession("api"),
+			wantErr:      ErrGracefulExit,
+			wantSwitches: []string{"api:1"},
+			wantSessions: []string{"api"},
+		},
+		{
+			name:         "prefix of an existing session is a new session",
+			driver:       &FakeDriver{Sessions: []FakeSession{{Name: "api-server"}}, Current: "api-server"},
+			session:      testSession("api"),
+			wantSwitches: []string{"api:1"},
+			wantCurrent:  "api",
+			wantSessions: []string{"api-server", "api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CreateAndSwitchSession(tt.driver, cfg, tt.session)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateAndSwitchSession() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(tt.driver.Switches, tt.wantSwitches) {
+				t.Errorf("Switches = %v, want %v", tt.driver.Switches, tt.wantSwitches)
+			}
+			if tt.driver.Current != tt.wantCurrent {
+				t.Errorf("Current = %q, want %q", tt.driver.Current, tt.wantCurrent)
+			}
+			if got := GetTmuxSessionNames(tt.driver); !slices.Equal(got, tt.wantSessions) {
+				t.Errorf("sessions = %v, want %v", got, tt.wantSessions)
+			}
+		})
+	}
+}
+
+func TestCreateSessionRecordsWindows(t *testing.T) {
+	d := &FakeDriver{}
+	session := testSession("api")
+
+	if err := CreateSession(d, session); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	got, ok := d.Session("api")
+	if !ok {
+		t.Fatal("session api was not created")
+	}
+	if got.Path != session.Path {
+		t.Errorf("Path = %q, want %q", got.Path, session.Path)
+	}
+	if len(got.Windows) != 2 || got.Windows[0].Name != "editor" || len(got.Windows[1].Panes) != 1 {
+		t.Errorf("Windows = %+v, want the session layout", got.Windows)
+	}
+}
+
+func TestCreateSessionWithoutWindows(t *testing.T) {
+	d := &FakeDriver{}
+	if err := CreateSession(d, models.Session{Name: "empty"}); err == nil {
+		t.Fatal("CreateSession() succeeded without windows")
+	}
+	if d.HasSession("empty") {
+		t.Error("session was created without windows")
+	}
+}
+
+func TestSessionHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &models.Config{Settings: models.Settings{TmuxBase: 1}}
+	d := NewFakeDriver("main")
+	d.Current = "main"
+
+	if err := CreateDetachedSession(d, cfg, testSession("api")); err != nil {
+		t.Fatalf("CreateDetachedSession() error = %v", err)
+	}
+	if err := CreateAndSwitchSession(d, cfg, testSession("web")); err != nil {
+		t.Fatalf("CreateAndSwitchSession() error = %v", err)
+	}
+
+	store, err := history.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api", "web"} {
+		if got := store.Entries[name].Count; got != 1 {
+			t.Errorf("history count of %s = %d, want 1", name, got)
+		}
+	}
+}
+
+func TestCreateSessionInvalidName(t *testing.T) {
+	d := &FakeDriver{}
+	window := []models.Window{{Name: "main"}}
+	for _, name := range []string{"foo.bar", "foo:bar"} {
+		if err := CreateSession(d, models.Session{Name: name, Layout: models.SessionLayout{Windows: window}}); err == nil {
+			t.Errorf("CreateSession(%q) succeeded", name)
+		}
+	}
+	if len(d.Sessions) != 0 {
+		t.Errorf("Sessions = %+v, want none", d.Sessions)
+	}
+}
+
+func TestSwitchToMissingSession(t *testing.T) {
+	d := NewFakeDriver("main")
+	d.Current = "main"
+
+	if err := SwitchToExistingSession(d, &models.Config{}, "gone"); err == nil {
+		t.Fatal("SwitchToExistingSession() succeeded for a missing session")
+	}
+	if len(d.Switches) != 0 {
+		t.Errorf("Switches = %v, want none", d.Switches)
+	}
+}
+
+func TestGetSessionsExceptCurrent(t *testing.T) {
+	d := NewFakeDriver("a", "b", "c")
+
+	if got, want := GetSessionsExceptCurrent(d, "b"), []string{"a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("GetSessionsExceptCurrent() = %v, want %v", got, want)
+	}
+
+	d.Errors = map[string]error{"ListSessions": errors.New("no server")}
+	if got := GetSessionsExceptCurrent(d, "b"); len(got) != 0 {
+		t.Errorf("GetSessionsExceptCurrent() = %v on error, want none", got)
+	}
+}
+
+func TestSessionHooksFire(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	events := filepath.Join(t.TempDir(), "events")
+	record := func(event string) string {
+		return `echo "` + event + ` $MUXLY_SESSION $MUXLY_TEMPLATE" >> ` + events
+	}
+	cfg := &models.Config{
+		Settings: models.Settings{TmuxBase: 1},
+		Templates: []models.SessionTemplate{{
+			Name: "go",
+			Hooks: models.Hooks{
+				OnCreate: record("create"),
+				OnAttach: record("attach"),
+				OnDetach: record("detach"),
+				OnKill:   record("kill"),
+			},
+		}},
+	}
+	d := NewFakeDriver("main")
+	d.Current = "main"
+
+	session := testSession("api")
+	session.Template = "go"
+	if err := CreateAndSwitchSession(d, cfg, session); err != nil {
+		t.Fatalf("CreateAndSwitchSession() error = %v", err)
+	}
+	// Leaving api for main detaches from api; main has no template hooks.
+	if err := SwitchToExistingSession(d, cfg, "main"); err != nil {
+		t.Fatalf("SwitchToExistingSession() error = %v", err)
+	}
+	if err := KillSession(d, cfg, "api"); err != nil {
+		t.Fatalf("KillSession() error = %v", err)
+	}
+
+	data, err := os.ReadFile(events)
+	if err != nil {
+		t.Fatalf("no hooks ran: %v", err)
+	}
+	want := "create api go\nattach api go\ndetach api go\nkill api go\n"
+	if string(data) != want {
+		t.Errorf("hooks ran as\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestSessionHooksUseLoadedMuxlyFile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	events := filepath.Join(t.TempDir(), "events")
+	record := func(event string) string {
+		return `echo "` + event + `" >> ` + events
+	}
+	muxly := "hooks:\n  on_create: " + record("file") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, ".muxly"), []byte(muxly), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &models.Config{Settings: models.Settings{TmuxBase: 1, MuxlyFilePolicy: config.MuxlyFileAllow}}
+	d := NewFakeDriver("main")
+	d.Current = "main"
+
+	session := testSession("api")
+	session.Path = dir
+	session.MuxlyHooks = &models.Hooks{OnCreate: record("create"), OnAttach: record("attach")}
+	if err := CreateAndSwitchSession(d, cfg, session); err != nil {
+		t.Fatalf("CreateAndSwitchSession() error = %v", err)
+	}
+
+	data, err := os.ReadFile(events)
+	if err != nil {
+		t.Fatalf("no hooks ran: %v", err)
+	}
+	if want := "create\nattach\n"; string(data) != want {
+		t.Errorf("hooks ran as\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestRenameSession(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := &models.Config{Settings: models.Settings{TmuxBase: 1}}
+	d := NewFakeDriver("main")
+	d.Current = "main"
+
+	session := testSession("api")
+	session.Template = "go"
+	if err := CreateAndSwitchSession(d, cfg, session); err != nil {
+		t.Fatalf("CreateAndSwitchSession() error = %v", err)
+	}
+	if err := RenameSession(d, "api", "api-v1"); err != nil {
+		t.Fatalf("RenameSession() error = %v", err)
+	}
+
+	if d.HasSession("api") || d.Current != "api-v1" {
+		t.Errorf("sessions = %v, current = %q, want api renamed to api-v1", GetTmuxSessionNames(d), d.Current)
+	}
+	if tmpl, _ := d.SessionOption("api-v1", TemplateOption); tmpl != "go" {
+		t.Errorf("template option = %q after rename, want go", tmpl)
+	}
+
+	store, err := history.LoadDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Entries["api"]; ok || store.Entries["api-v1"].Count != 1 {
+		t.Errorf("history = %v, want the entry of api moved to api-v1", store.Entries)
+	}
+
+	if err := RenameSession(d, "api-v1", "main"); err == nil {
+		t.Error("RenameSession() onto a running session succeeded")
+	}
+}
+
+func TestAttachHooks(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	events := filepath.Join(t.TempDir(), "events")
+	cfg := &models.Config{
+		Settings: models.Settings{TmuxBase: 1},
+		Hooks: models.Hooks{
+			OnAttach: "echo attach >> " + events,
+			OnDetach: "echo detach >> " + events,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		errors   map[string]error
+		attached bool
+		want     string
+	}{
+		{name: "attach and detach", attached: true, want: "attach\ndetach\n"},
+		{name: "failed attach runs no hooks", errors: map[string]error{"AttachSession": errors.New("open terminal failed")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(events)
+			d := NewFakeDriver("api")
+			d.Errors = tt.errors
+
+			err := SwitchToExistingSession(d, cfg, "api")
+			if errors.Is(err, ErrGracefulExit) != tt.attached {
+				t.Fatalf("SwitchToExistingSession() error = %v, attached %v", err, tt.attached)
+			}
+
+			data, _ := os.ReadFile(events)
+			if string(data) != tt.want {
+				t.Errorf("hooks ran as %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package tmux
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/models"
+)
+
+// FakeSession is a session held by a FakeDriver.
+type FakeSession struct {
+	Name    string
+	Path    string
+	Windows []models.Window
+	// Options holds session options, such as TemplateOption.
+	Options map[string]string
+	// Group is the session this one was grouped with, if any. Windows are
+	// not actually shared.
+	Group string
+}
+
+// FakeDriver is an in-memory Driver for tests. It keeps the sessions it
+// creates in creation order and records every switch and attach, so tests
+// can assert on the outcome of a whole command flow.
+//
+// The zero value is a stopped server seen from outside tmux. Set Current to
+// run inside a client attached to that session.
+type FakeDriver struct {
+	Sessions []FakeSession
+	// Current is the session of the client muxly runs in, or "" outside tmux.
+	Current string
+	// Switches records the targets passed to SwitchClient and AttachSession.
+	Switches []string
+	Buffer   string
+	// WindowBase is reported as the server's base-index.
+	WindowBase int
+	// Errors makes the named method (e.g. "KillSession") fail with the
+	// given error.
+	Errors map[string]error
+}
+
+var _ Driver = (*FakeDriver)(nil)
+
+// NewFakeDriver returns a FakeDriver running the named sessions, each with a
+// single window.
+func NewFakeDriver(names ...string) *FakeDriver {
+	f := &FakeDriver{}
+	for _, name := range names {
+		f.Sessions = append(f.Sessions, FakeSession{
+			Name:    name,
+			Windows: []models.Window{{Name: "window"}},
+		})
+	}
+	return f
+}
+
+// Session returns the named session.
+func (f *FakeDriver) Session(name string) (FakeSession, bool) {
+	i := f.index(name)
+	if i < 0 {
+		return FakeSession{}, false
+	}
+	return f.Sessions[i], true
+}
+
+func (f *FakeDriver) index(name string) int {
+	return slices.IndexFunc(f.Sessions, func(s FakeSession) bool { return s.Name == name })
+}
+
+func (f *FakeDriver) fail(method string) error {
+	return f.Errors[method]
+}
+
+func (f *FakeDriver) ListSessions() ([]string, error) {
+	if err := f.fail("ListSessions"); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, s := range f.Sessions {
+		names = append(names, s.Name)
+	}
+	return names, nil
+}
+
+// ListSessionInfo reports the current session, if any, as the only
+// attached one.
+func (f *FakeDriver) ListSessionInfo() ([]SessionInfo, error) {
+	if err := f.fail("ListSessionInfo"); err != nil {
+		return nil, err
+	}
+
+	var sessions []SessionInfo
+	for _, s := range f.Sessions {
+		info := SessionInfo{Name: s.Name, Path: s.Path, Windows: len(s.Windows)}
+		if s.Name == f.Current {
+			info.Attached = 1
+		}
+		sessions = append(sessions, info)
+	}
+	return sessions, nil
+}
+
+func (f *FakeDriver) HasSession(name string) bool {
+	return f.index(name) >= 0
+}
+
+func (f *FakeDriver) CurrentSession() string {
+	return f.Current
+}
+
+func (f *FakeDriver) InsideTmux() bool {
+	return f.Current != ""
+}
+
+func (f *FakeDriver) ServerRunning() bool {
+	return len(f.Sessions) > 0
+}
+
+func (f *FakeDriver) BaseIndex() (int, error) {
+	if err := f.fail("BaseIndex"); err != nil {
+		return 0, err
+	}
+	if !f.ServerRunning() {
+		return 0, fmt.Errorf("reading base-index: no server running")
+	}
+	return f.WindowBase, nil
+}
+
+func (f *FakeDriver) CreateSession(session models.Session) error {
+	if err := f.fail("CreateSession"); err != nil {
+		return err
+	}
+	if f.HasSession(session.Name) {
+		return fmt.Errorf("duplicate session: %s", session.Name)
+	}
+
+	created := FakeSession{
+		Name:    session.Name,
+		Path:    session.Path,
+		Windows: slices.Clone(session.Layout.Windows),
+		Group:   session.Group,
+	}
+	if session.Group != "" && !f.HasSession(session.Group) {
+		return fmt.Errorf("can't find session: %s", session.Group)
+	}
+	if session.Template != "" {
+		created.Options = map[string]string{TemplateOption: session.Template}
+	}
+	f.Sessions = append(f.Sessions, created)
+	return nil
+}
+
+// SwitchClient accepts a session name or a "session:window" target and
+// makes that session current.
+func (f *FakeDriver) SwitchClient(target string) error {
+	if err := f.fail("SwitchClient"); err != nil {
+		return err
+	}
+	name, _, _ := strings.Cut(target, ":")
+	if !f.HasSession(name) {
+		return fmt.Errorf("can't find session: %s", name)
+	}
+
+	f.Switches = append(f.Switches, target)
+	f.Current = name
+	return nil
+}
+
+// AttachSession records the attach and returns at once, as if the client
+// detached immediately after attaching.
+func (f *FakeDriver) AttachSession(target string, attached func()) error {
+	if err := f.fail("AttachSession"); err != nil {
+		return err
+	}
+	name, _, _ := strings.Cut(target, ":")
+	if !f.HasSession(name) {
+		return fmt.Errorf("can't find session: %s", name)
+	}
+
+	f.Switches = append(f.Switches, target)
+	attached()
+	return nil
+}
+
+func (f *FakeDriver) KillSession(name string) error {
+	if err := f.fail("KillSession"); err != nil {
+		return err
+	}
+	i := f.index(name)
+	if i < 0 {
+		return fmt.Errorf("killing session: can't find session: %s", name)
+	}
+
+	f.Sessions = slices.Delete(f.Sessions, i, i+1)
+	if f.Current == name {
+		f.Current = ""
+	}
+	return nil
+}
+
+func (f *FakeDriver) KillServer() error {
+	if err := f.fail("KillServer"); err != nil {
+		return err
+	}
+
+	f.Sessions = nil
+	f.Current = ""
+	return nil
+}
+
+func (f *FakeDriver) RenameSession(old, new string) error {
+	if err := f.fail("RenameSession"); err != nil {
+		return err
+	}
+	i := f.index(old)
+	if i < 0 {
+		return fmt.Errorf("renaming session: can't find session: %s", old)
+	}
+	if f.HasSession(new) {
+		return fmt.Errorf("renaming session: duplicate session: %s", new)
+	}
+
+	f.Sessions[i].Name = new
+	if f.Current == old {
+		f.Current = new
+	}
+	return nil
+}
+
+func (f *FakeDriver) SessionPath(name string) (string, error) {
+	s, ok := f.Session(name)
+	if !ok {
+		return "", fmt.Errorf("reading session path: can't find session: %s", name)
+	}
+	return s.Path, nil
+}
+
+func (f *FakeDriver) SessionOption(name, option string) (string, error) {
+	s, ok := f.Session(name)
+	if !ok {
+		return "", fmt.Errorf("reading session option %s: can't find session: %s", option, name)
+	}
+	return s.Options[option], nil
+}
+
+func (f *FakeDriver) SetSessionOption(name, option, value string) error {
+	if err := f.fail("SetSessionOption"); err != nil {
+		return err
+	}
+	i := f.index(name)
+	if i < 0 {
+		return fmt.Errorf("setting session option %s: can't find session: %s", option, name)
+	}
+
+	if f.Sessions[i].Options == nil {
+		f.Sessions[i].Options = make(map[string]string)
+	}
+	f.Sessions[i].Options[option] = value
+	return nil
+}
+
+// ListPanes reports each window's first pane in the window's directory and
+// running its cmd, followed by its extra panes.
+func (f *FakeDriver) ListPanes(name string) ([]PaneInfo, error) {
+	s, ok := f.Session(name)
+	if !ok {
+		return nil, fmt.Errorf("listing panes: can't find session: %s", name)
+	}
+
+	var panes []PaneInfo
+	for i, w := range s.Windows {
+		window := PaneInfo{WindowIndex: i, WindowName: w.Name, WindowLayout: w.Layout}
+
+		first := window
+		first.Path, first.Command, first.CommandLine = paneDir(s.Path, w.Path), programName(w.Cmd), w.Cmd
+		panes = append(panes, first)
+		for _, p := range w.Panes {
+			pane := window
+			pane.Path, pane.Command, pane.CommandLine = paneDir(s.Path, p.Path), programName(p.Cmd), p.Cmd
+			panes = append(panes, pane)
+		}
+	}
+	return panes, nil
+}
+
+// programName returns the name of the program cmd runs, as tmux reports it
+// in pane_current_command.
+func programName(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
+
+func (f *FakeDriver) ListWindows(name string) (string, error) {
+	s, ok := f.Session(name)
+	if !ok {
+		return "", fmt.Errorf("listing windows: can't find session: %s", name)
+	}
+
+	var b strings.Builder
+	for i, w := range s.Windows {
+		active := ""
+		if i == 0 {
+			active = " *"
+		}
+		fmt.Fprintf(&b, "%d: %s (%d panes)%s\n", i, w.Name, len(w.Panes)+1, active)
+	}
+	return b.String(), nil
+}
+
+func (f *FakeDriver) CapturePane(name string) (string, error) {
+	if !f.HasSession(name) {
+		return "", fmt.Errorf("capturing pane: can't find session: %s", name)
+	}
+	return "", nil
+}
+
+func (f *FakeDriver) SetBuffer(text string) error {
+	if err := f.fail("SetBuffer"); err != nil {
+		return err
+	}
+
+	f.Buffer = text
+	return nil
+}
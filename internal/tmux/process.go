@@ -0,0 +1,64 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// foregroundCommandLine returns the command line of the foreground process
+// in the terminal of process pid, the pane's first process. That is the
+// program tmux reports as pane_current_command, here with its arguments. It
+// returns "" when the foreground process cannot be determined.
+func foregroundCommandLine(pid int) string {
+	output, err := exec.Command("ps", "-o", "tpgid=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return ""
+	}
+	fg, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil || fg <= 0 {
+		return ""
+	}
+	return commandLine(fg)
+}
+
+// commandLine returns the arguments of process pid as a shell command. /proc
+// gives the exact arguments; elsewhere ps prints them joined by spaces.
+func commandLine(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		output, err := exec.Command("ps", "-o", "args=", "-p", strconv.Itoa(pid)).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	// Programs that set their process title, such as npm, overwrite their
+	// arguments with a single string that is already a command line.
+	if len(args) == 1 {
+		return args[0]
+	}
+	return joinArgs(args)
+}
+
+// plainArg matches arguments that need no quoting in a shell.
+var plainArg = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// joinArgs joins args into a shell command, quoting those that need it.
+func joinArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = arg
+		if !plainArg.MatchString(arg) {
+			quoted[i] = utility.ShellQuote(arg)
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,44 @@
+package tmux
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestJoinArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"npm", "run", "dev"}, want: "npm run dev"},
+		{args: []string{"go", "test", "./...", "-run", "TestX/sub case"}, want: `go test ./... -run 'TestX/sub case'`},
+		{args: []string{"grep", "it's", "--color=auto"}, want: `grep 'it'\''s' --color=auto`},
+	}
+
+	for _, tt := range tests {
+		if got := joinArgs(tt.args); got != tt.want {
+			t.Errorf("joinArgs(%q) = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start sleep: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	// The new program's arguments may show up a moment after it started.
+	var got string
+	for deadline := time.Now().Add(time.Second); got == "" && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		got = commandLine(cmd.Process.Pid)
+	}
+	if got != "sleep 30" {
+		t.Errorf("commandLine() = %q, want %q", got, "sleep 30")
+	}
+}
@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/Pairadux/muxly/internal/config"
 	"github.com/Pairadux/muxly/internal/constants"
+	"github.com/Pairadux/muxly/internal/history"
+	"github.com/Pairadux/muxly/internal/hooks"
 	"github.com/Pairadux/muxly/internal/models"
 	"github.com/mitchellh/go-homedir"
 )
@@ -21,24 +23,18 @@ var ErrGracefulExit = errors.New("graceful exit")
 
 const DefaultShell = "/bin/bash"
 
+// TemplateOption is the tmux session option recording the template a session
+// was created from, so the template's hooks can be found again later.
+const TemplateOption = "@muxly_template"
+
 // GetTmuxSessionNames returns a slice of all active tmux session names.
 // Returns an empty slice if tmux is not available or if there's an error.
-func GetTmuxSessionNames() []string {
-	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
-	output, err := cmd.Output()
+func GetTmuxSessionNames(d Driver) []string {
+	sessions, err := d.ListSessions()
 	if err != nil {
 		return nil
 	}
 
-	// Parse session names from output, one per line
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	sessions := make([]string, 0, len(lines))
-	for _, line := range lines {
-		if line != "" {
-			sessions = append(sessions, line)
-		}
-	}
-
 	return sessions
 }
 
@@ -46,23 +42,18 @@ func GetTmuxSessionNames() []string {
 //
 // This is useful for getting a list of sessions that can be switched to,
 // excluding the session the user is currently in.
-func GetSessionsExceptCurrent(current string) []string {
-	sessions := GetTmuxSessionNames()
+func GetSessionsExceptCurrent(d Driver, current string) []string {
+	sessions := GetTmuxSessionNames(d)
 	if idx := slices.Index(sessions, current); idx >= 0 {
 		sessions = slices.Delete(sessions, idx, idx+1)
 	}
 	return sessions
 }
 
-// HasTmuxSession checks if a tmux session with the given name exists.
-func HasTmuxSession(name string) bool {
-	return exec.Command("tmux", "has-session", "-t", name).Run() == nil
-}
-
 // GetTmuxSessionSet returns a set (map[string]bool) of active session names
 // for efficient membership testing when you need to check many sessions.
-func GetTmuxSessionSet() map[string]bool {
-	names := GetTmuxSessionNames()
+func GetTmuxSessionSet(d Driver) map[string]bool {
+	names := GetTmuxSessionNames(d)
 	// Convert session names to a set for efficient membership testing
 	sessions := make(map[string]bool, len(names))
 	for _, name := range names {
@@ -72,58 +63,151 @@ func GetTmuxSessionSet() map[string]bool {
 	return sessions
 }
 
-// GetCurrentTmuxSession returns the name of the current tmux session.
-// Returns an empty string if not running inside tmux or if there's
-// an error retrieving the session name.
-func GetCurrentTmuxSession() string {
-	if os.Getenv(constants.EnvTmux) == "" {
-		return ""
-	}
-
-	cmd := exec.Command("tmux", "display-message", "-p", "#{session_name}")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-
-	return strings.TrimSpace(string(output))
-}
-
 // SwitchToExistingSession switches to an existing tmux session by name.
 // This function assumes the session already exists and will return an error if it doesn't.
 // It handles both cases of running inside tmux (switch-client) and outside tmux (attach-session).
-func SwitchToExistingSession(cfg *models.Config, name string) error {
-	if !HasTmuxSession(name) {
+//
+// The switch is recorded in the history store before switching, since attaching
+// blocks until the client detaches. CreateAndSwitchSession records through here too.
+//
+// The session's on_attach hooks run once muxly switches to it, or once the
+// client has attached. Its on_detach hooks run when the attached client
+// detaches, and not at all when it never attached; when switching, those of
+// the session being left run.
+func SwitchToExistingSession(d Driver, cfg *models.Config, name string) error {
+	if !d.HasSession(name) {
 		return fmt.Errorf("session '%s' does not exist", name)
 	}
+	return switchToSession(d, cfg, name, HookSession(d, name))
+}
+
+// switchToSession is SwitchToExistingSession for a session known to exist,
+// running the hooks of hookSession.
+func switchToSession(d Driver, cfg *models.Config, name string, hookSession hooks.Session) error {
+	if err := history.Record(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record session history: %v\n", err)
+	}
 
 	target := getSessionTarget(cfg, name)
 
-	if os.Getenv(constants.EnvTmux) == "" {
-		return attachToSession(target, name)
-	} else {
-		return switchClientToSession(target, name)
+	if !d.InsideTmux() {
+		attached, err := attachToSession(d, target, name, func() {
+			hooks.Run(cfg, hooks.OnAttach, hookSession)
+		})
+		if attached {
+			hooks.Run(cfg, hooks.OnDetach, hookSession)
+		}
+		return err
 	}
-}
 
-// IsTmuxServerRunning checks if a tmux server is currently running
-func IsTmuxServerRunning() bool {
-	cmd := exec.Command("tmux", "list-sessions")
-	return cmd.Run() == nil
+	previous := d.CurrentSession()
+	var left hooks.Session
+	if previous != "" && previous != name {
+		left = HookSession(d, previous)
+	}
+	if err := switchClientToSession(d, target, name); err != nil {
+		return err
+	}
+	if left.Name != "" {
+		hooks.Run(cfg, hooks.OnDetach, left)
+	}
+	hooks.Run(cfg, hooks.OnAttach, hookSession)
+	return nil
 }
 
 // CreateAndSwitchSession creates a new tmux session and switches to it.
-// If the session already exists, it just switches to it.
-func CreateAndSwitchSession(cfg *models.Config, session models.Session) error {
-	if HasTmuxSession(session.Name) {
-		return SwitchToExistingSession(cfg, session.Name)
+// If the session already exists, it just switches to it. The on_create hooks
+// run between creating the session and switching to it.
+func CreateAndSwitchSession(d Driver, cfg *models.Config, session models.Session) error {
+	if d.HasSession(session.Name) {
+		return SwitchToExistingSession(d, cfg, session.Name)
 	}
 
-	if err := CreateSession(session); err != nil {
+	if err := createSession(d, cfg, session); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
-	return SwitchToExistingSession(cfg, session.Name)
+	return switchToSession(d, cfg, session.Name, newHookSession(session))
+}
+
+// CreateDetachedSession creates a session without switching to it and runs
+// its on_create hooks. The session is recorded in the history store like
+// one switched to, so sessions started in the background count towards
+// recent and frecency order too.
+func CreateDetachedSession(d Driver, cfg *models.Config, session models.Session) error {
+	if err := createSession(d, cfg, session); err != nil {
+		return err
+	}
+
+	if err := history.Record(session.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record session history: %v\n", err)
+	}
+	return nil
+}
+
+// createSession creates the session and runs its on_create hooks.
+func createSession(d Driver, cfg *models.Config, session models.Session) error {
+	if err := CreateSession(d, session); err != nil {
+		return err
+	}
+
+	hooks.Run(cfg, hooks.OnCreate, newHookSession(session))
+	return nil
+}
+
+// newHookSession describes a session muxly is creating for its hooks.
+func newHookSession(session models.Session) hooks.Session {
+	return hooks.Session{Name: session.Name, Path: session.Path, Template: session.Template, Muxly: session.MuxlyHooks}
+}
+
+// RenameSession renames the session old to new and moves its history along.
+// tmux keeps the session's options, such as TemplateOption, across the rename.
+func RenameSession(d Driver, old, new string) error {
+	if err := d.RenameSession(old, new); err != nil {
+		return err
+	}
+
+	if err := history.Rename(old, new); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update session history: %v\n", err)
+	}
+	return nil
+}
+
+// KillSession kills the named session and then runs its on_kill hooks.
+func KillSession(d Driver, cfg *models.Config, name string) error {
+	hookSession := HookSession(d, name)
+	if err := d.KillSession(name); err != nil {
+		return err
+	}
+
+	hooks.Run(cfg, hooks.OnKill, hookSession)
+	return nil
+}
+
+// KillServer kills the tmux server and then runs the on_kill hooks of every
+// session it ran.
+func KillServer(d Driver, cfg *models.Config) error {
+	var killed []hooks.Session
+	for _, name := range GetTmuxSessionNames(d) {
+		killed = append(killed, HookSession(d, name))
+	}
+	if err := d.KillServer(); err != nil {
+		return err
+	}
+
+	for _, s := range killed {
+		hooks.Run(cfg, hooks.OnKill, s)
+	}
+	return nil
+}
+
+// HookSession describes a running session for its hooks, reading its
+// directory and template from tmux.
+func HookSession(d Driver, name string) hooks.Session {
+	s := hooks.Session{Name: name}
+	s.Path, _ = d.SessionPath(name)
+	s.Template, _ = d.SessionOption(name, TemplateOption)
+	return s
 }
 
 // getSessionTarget returns the target string for tmux commands,
@@ -137,49 +221,48 @@ func getSessionTarget(cfg *models.Config, name string) string {
 	return name
 }
 
-// attachToSession attaches to a session when not currently in tmux.
-// Returns ErrGracefulExit on successful attach or when server is not running.
-func attachToSession(target, fallbackName string) error {
-	if !IsTmuxServerRunning() {
-		return ErrGracefulExit
+// attachToSession attaches to a session when not currently in tmux, calling
+// onAttach once the client has attached. Returns ErrGracefulExit on
+// successful attach or when server is not running. attached reports whether
+// the client attached at all, so a server that is gone is not mistaken for a
+// detach.
+func attachToSession(d Driver, target, fallbackName string, onAttach func()) (attached bool, err error) {
+	if !d.ServerRunning() {
+		return false, ErrGracefulExit
 	}
 
-	cmd := exec.Command("tmux", "attach-session", "-t", target)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	notify := func() {
+		attached = true
+		onAttach()
+	}
 
-	if err := cmd.Run(); err != nil {
+	if err := d.AttachSession(target, notify); err != nil {
 		// If targeting a specific window failed, try just the session name
-		if target != fallbackName {
-			cmd := exec.Command("tmux", "attach-session", "-t", fallbackName)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			cmd.Stdin = os.Stdin
-			if cmd.Run() == nil {
-				return ErrGracefulExit
+		if target != fallbackName && !attached {
+			if d.AttachSession(fallbackName, notify) == nil {
+				return attached, ErrGracefulExit
 			}
-			if !IsTmuxServerRunning() {
-				return ErrGracefulExit
+			if !d.ServerRunning() {
+				return attached, ErrGracefulExit
 			}
-			return err
+			return attached, err
 		}
 
-		if !IsTmuxServerRunning() {
-			return ErrGracefulExit
+		if !d.ServerRunning() {
+			return attached, ErrGracefulExit
 		}
-		return fmt.Errorf("attaching to session: %w", err)
+		return attached, fmt.Errorf("attaching to session: %w", err)
 	}
 
-	return ErrGracefulExit
+	return attached, ErrGracefulExit
 }
 
 // switchClientToSession switches to a session when already in tmux
-func switchClientToSession(target, fallbackName string) error {
-	if err := exec.Command("tmux", "switch-client", "-t", target).Run(); err != nil {
+func switchClientToSession(d Driver, target, fallbackName string) error {
+	if err := d.SwitchClient(target); err != nil {
 		// If targeting a specific window failed, try just the session name
 		if target != fallbackName {
-			return exec.Command("tmux", "switch-client", "-t", fallbackName).Run()
+			return d.SwitchClient(fallbackName)
 		}
 
 		return fmt.Errorf("switching to session: %w", err)
@@ -189,31 +272,68 @@ func switchClientToSession(target, fallbackName string) error {
 }
 
 // CreateSession creates a new tmux session using the provided session configuration.
-// The session layout must contain at least one window definition.
-//
-// The first window is created with the new-session command, and subsequent
-// windows are added using new-window. Each window can optionally specify a
-// command to run upon creation.
-func CreateSession(session models.Session) error {
+// The session layout must contain at least one window definition, and the
+// name must not contain '.' or ':', which tmux replaces with '_': the rest
+// of the session's commands would then target a session that does not exist.
+func CreateSession(d Driver, session models.Session) error {
 	if len(session.Layout.Windows) == 0 {
 		return fmt.Errorf("no windows defined in session layout")
 	}
+	if strings.ContainsAny(session.Name, ".:") {
+		return fmt.Errorf("invalid session name %q: tmux does not allow '.' or ':'", session.Name)
+	}
 
-	// REFACTOR: Consider using a single tmux command with multiple operations for better performance
-	w0 := session.Layout.Windows[0]
-	args := buildWindowArgs(true, session.Name, w0.Name, session.Path, w0.Cmd)
-	if err := exec.Command("tmux", args...).Run(); err != nil {
-		return err
+	return d.CreateSession(session)
+}
+
+// commandSeparator separates the commands of a single tmux invocation.
+const commandSeparator = ";"
+
+// buildSessionArgs chains the commands that build session into a single
+// tmux argument list: new-session for the first window, new-window for the
+// others, each followed by its pane splits and layout.
+//
+// A session joining a group starts out with the group's windows, so it is
+// created without a window of its own and all of its windows are added with
+// new-window.
+func buildSessionArgs(session models.Session) []string {
+	var commands [][]string
+	if session.Group != "" {
+		commands = append(commands, []string{"new-session", "-d", "-s", session.Name, "-t", session.Group, "-c", session.Path})
+	}
+	for i, w := range session.Layout.Windows {
+		isFirst := i == 0 && session.Group == ""
+		commands = append(commands, buildWindowArgs(isFirst, session.Name, w.Name, paneDir(session.Path, w.Path), w.Cmd))
+		for _, p := range w.Panes {
+			commands = append(commands, buildPaneArgs(session.Name, session.Path, p))
+		}
+		if w.Layout != "" {
+			commands = append(commands, buildLayoutArgs(session.Name, w.Layout))
+		}
+	}
+	if session.Template != "" {
+		commands = append(commands, []string{"set-option", "-t", session.Name, TemplateOption, session.Template})
 	}
 
-	for _, w := range session.Layout.Windows[1:] {
-		args := buildWindowArgs(false, session.Name, w.Name, session.Path, w.Cmd)
-		if err := exec.Command("tmux", args...).Run(); err != nil {
-			return err
+	var args []string
+	for i, command := range commands {
+		if i > 0 {
+			args = append(args, commandSeparator)
+		}
+		for _, arg := range command {
+			args = append(args, escapeSeparator(arg))
 		}
 	}
+	return args
+}
 
-	return nil
+// escapeSeparator keeps a trailing semicolon in arg literal. tmux treats any
+// argument ending in ";" as the end of a command unless it is escaped.
+func escapeSeparator(arg string) string {
+	if trimmed, ok := strings.CutSuffix(arg, commandSeparator); ok {
+		return trimmed + `\` + commandSeparator
+	}
+	return arg
 }
 
 // buildWindowArgs constructs tmux command arguments for creating a window.
@@ -228,52 +348,72 @@ func buildWindowArgs(isFirst bool, sessionName, windowName, dir, cmd string) []s
 		args = []string{"new-window", "-t", sessionName, "-n", windowName, "-c", dir}
 	}
 
-	if cmd != "" {
-		shell := os.Getenv(constants.EnvShell)
-		if shell == "" {
-			shell = DefaultShell
-		}
-		cmdStr := cmd + "; exec " + shell
-		args = append(args, "--", shell, "-lc", cmdStr)
-	}
-
-	return args
+	return append(args, buildShellArgs(cmd)...)
 }
 
-// KillSession terminates the specified tmux session.
+// buildPaneArgs constructs tmux command arguments for splitting a pane off the
+// session's current window, which is always the most recently created one.
 //
-// Returns an error if the session doesn't exist or if the kill operation fails.
-func KillSession(target string) error {
-	if err := exec.Command("tmux", "kill-session", "-t", target).Run(); err != nil {
-		return fmt.Errorf("killing session: %w", err)
+// The split is detached (-d) so focus stays on the window's first pane.
+func buildPaneArgs(sessionName, sessionDir string, pane models.Pane) []string {
+	args := []string{"split-window", "-d", "-t", sessionName}
+	if pane.Split == "horizontal" {
+		args = append(args, "-h")
+	} else {
+		args = append(args, "-v")
 	}
+	if pane.Size > 0 {
+		args = append(args, "-l", fmt.Sprintf("%d%%", pane.Size))
+	}
+	args = append(args, "-c", paneDir(sessionDir, pane.Path))
 
-	return nil
+	return append(args, buildShellArgs(pane.Cmd)...)
 }
 
-// KillServer terminates the entire tmux server and all sessions.
-//
-// This is a destructive operation that will close all tmux sessions.
-// Returns an error if the kill operation fails.
-func KillServer() error {
-	if err := exec.Command("tmux", "kill-server").Run(); err != nil {
-		return fmt.Errorf("killing server: %w", err)
+// buildLayoutArgs constructs tmux command arguments for arranging the panes
+// of the session's current window.
+func buildLayoutArgs(sessionName, layout string) []string {
+	return []string{"select-layout", "-t", sessionName, layout}
+}
+
+// paneDir resolves a window's or pane's working directory against the
+// session directory.
+func paneDir(sessionDir, path string) string {
+	if path == "" {
+		return sessionDir
+	}
+	if filepath.IsAbs(path) {
+		return path
 	}
+	return filepath.Join(sessionDir, path)
+}
 
-	return nil
+// buildShellArgs wraps cmd with the user's login shell so the pane stays open
+// after the command exits. Returns nil when cmd is empty.
+func buildShellArgs(cmd string) []string {
+	if cmd == "" {
+		return nil
+	}
+
+	shell := os.Getenv(constants.EnvShell)
+	if shell == "" {
+		shell = DefaultShell
+	}
+	cmdStr := cmd + "; exec " + shell
+	return []string{"--", shell, "-lc", cmdStr}
 }
 
 // CreateSessionFromDefaultTemplate creates and switches to a session using the default template.
 // Used by the kill command when no other sessions exist.
-func CreateSessionFromDefaultTemplate(cfg *models.Config) error {
+func CreateSessionFromDefaultTemplate(d Driver, cfg *models.Config) error {
 	tmpl, found := config.DefaultTemplate(cfg)
 	if !found {
 		return fmt.Errorf("no default template configured")
 	}
 
 	sessionName := tmpl.Name
-	if HasTmuxSession(sessionName) {
-		return SwitchToExistingSession(cfg, sessionName)
+	if d.HasSession(sessionName) {
+		return SwitchToExistingSession(d, cfg, sessionName)
 	}
 
 	sessionPath := tmpl.Path
@@ -286,25 +426,27 @@ func CreateSessionFromDefaultTemplate(cfg *models.Config) error {
 	}
 
 	session := models.Session{
-		Name:   sessionName,
-		Path:   sessionPath,
-		Layout: models.SessionLayout{Windows: tmpl.Windows},
+		Name:     sessionName,
+		Path:     sessionPath,
+		Layout:   models.SessionLayout{Windows: tmpl.Windows},
+		Template: tmpl.Name,
 	}
 
-	return CreateAndSwitchSession(cfg, session)
+	return CreateAndSwitchSession(d, cfg, session)
 }
 
 // CreateSessionFromTemplate creates and switches to a session from a given template and path.
-func CreateSessionFromTemplate(cfg *models.Config, tmpl models.SessionTemplate, sessionPath, sessionName string) error {
-	if HasTmuxSession(sessionName) {
-		return SwitchToExistingSession(cfg, sessionName)
+func CreateSessionFromTemplate(d Driver, cfg *models.Config, tmpl models.SessionTemplate, sessionPath, sessionName string) error {
+	if d.HasSession(sessionName) {
+		return SwitchToExistingSession(d, cfg, sessionName)
 	}
 
 	session := models.Session{
-		Name:   sessionName,
-		Path:   sessionPath,
-		Layout: models.SessionLayout{Windows: tmpl.Windows},
+		Name:     sessionName,
+		Path:     sessionPath,
+		Layout:   models.SessionLayout{Windows: tmpl.Windows},
+		Template: tmpl.Name,
 	}
 
-	return CreateAndSwitchSession(cfg, session)
+	return CreateAndSwitchSession(d, cfg, session)
 }
@@ -1,8 +1,10 @@
 package tmux
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 
 	"github.com/Pairadux/muxly/internal/models"
@@ -84,6 +86,57 @@ func TestBuildWindowArgsDefaultShell(t *testing.T) {
 	}
 }
 
+func TestBuildPaneArgs(t *testing.T) {
+	originalShell := os.Getenv("SHELL")
+	defer os.Setenv("SHELL", originalShell)
+
+	os.Setenv("SHELL", "/bin/zsh")
+
+	tests := []struct {
+		name     string
+		pane     models.Pane
+		expected []string
+	}{
+		{
+			name:     "default split",
+			pane:     models.Pane{},
+			expected: []string{"split-window", "-d", "-t", "dev", "-v", "-c", "/home/user/code"},
+		},
+		{
+			name:     "horizontal split with size",
+			pane:     models.Pane{Split: "horizontal", Size: 30},
+			expected: []string{"split-window", "-d", "-t", "dev", "-h", "-l", "30%", "-c", "/home/user/code"},
+		},
+		{
+			name:     "relative path with command",
+			pane:     models.Pane{Split: "vertical", Path: "web", Cmd: "npm test"},
+			expected: []string{"split-window", "-d", "-t", "dev", "-v", "-c", "/home/user/code/web", "--", "/bin/zsh", "-lc", "npm test; exec /bin/zsh"},
+		},
+		{
+			name:     "absolute path",
+			pane:     models.Pane{Path: "/var/log"},
+			expected: []string{"split-window", "-d", "-t", "dev", "-v", "-c", "/var/log"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildPaneArgs("dev", "/home/user/code", tt.pane)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("buildPaneArgs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildLayoutArgs(t *testing.T) {
+	got := buildLayoutArgs("dev", "main-vertical")
+	expected := []string{"select-layout", "-t", "dev", "main-vertical"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("buildLayoutArgs() = %v, want %v", got, expected)
+	}
+}
+
 func TestGetSessionTarget(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -126,3 +179,172 @@ func TestGetSessionTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestExecDriverSocketArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   ExecDriver
+		expected []string
+	}{
+		{name: "default server", driver: ExecDriver{}, expected: []string{"list-sessions"}},
+		{name: "socket name", driver: ExecDriver{SocketName: "work"}, expected: []string{"-L", "work", "list-sessions"}},
+		{name: "socket path", driver: ExecDriver{SocketPath: "/tmp/ci.sock"}, expected: []string{"-S", "/tmp/ci.sock", "list-sessions"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.driver.command("list-sessions").Args[1:]
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("command() args = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecDriverInsideTmux(t *testing.T) {
+	t.Setenv("TMUX_TMPDIR", "/run/tmux")
+	workSocket := fmt.Sprintf("/run/tmux/tmux-%d/work", os.Getuid())
+
+	tests := []struct {
+		name     string
+		env      string
+		driver   ExecDriver
+		expected bool
+	}{
+		{name: "outside tmux", env: "", driver: ExecDriver{}, expected: false},
+		{name: "default server", env: "/tmp/tmux-1000/default,123,0", driver: ExecDriver{}, expected: true},
+		{name: "same named server", env: workSocket + ",123,0", driver: ExecDriver{SocketName: "work"}, expected: true},
+		{name: "other named server", env: workSocket + ",123,0", driver: ExecDriver{SocketName: "personal"}, expected: false},
+		{name: "same socket path", env: "/tmp/ci.sock,123,0", driver: ExecDriver{SocketPath: "/tmp/ci.sock"}, expected: true},
+		{name: "other socket path", env: workSocket + ",123,0", driver: ExecDriver{SocketPath: "/tmp/ci.sock"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.env)
+			if result := tt.driver.InsideTmux(); result != tt.expected {
+				t.Errorf("InsideTmux() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildSessionArgs(t *testing.T) {
+	t.Setenv("SHELL", "/bin/zsh")
+
+	tests := []struct {
+		name     string
+		session  models.Session
+		expected []string
+	}{
+		{
+			name: "single window",
+			session: models.Session{Name: "dev", Path: "/code", Layout: models.SessionLayout{Windows: []models.Window{
+				{Name: "main"},
+			}}},
+			expected: []string{"new-session", "-ds", "dev", "-n", "main", "-c", "/code"},
+		},
+		{
+			name: "windows with panes and layout",
+			session: models.Session{Name: "dev", Path: "/code", Layout: models.SessionLayout{Windows: []models.Window{
+				{Name: "editor", Cmd: "nvim"},
+				{Name: "run", Layout: "even-horizontal", Panes: []models.Pane{{Split: "horizontal"}}},
+			}}},
+			expected: []string{
+				"new-session", "-ds", "dev", "-n", "editor", "-c", "/code", "--", "/bin/zsh", "-lc", "nvim; exec /bin/zsh", ";",
+				"new-window", "-t", "dev", "-n", "run", "-c", "/code", ";",
+				"split-window", "-d", "-t", "dev", "-h", "-c", "/code", ";",
+				"select-layout", "-t", "dev", "even-horizontal",
+			},
+		},
+		{
+			name: "trailing semicolon stays literal",
+			session: models.Session{Name: "dev", Path: "/code", Layout: models.SessionLayout{Windows: []models.Window{
+				{Name: "odd;"},
+				{Name: "next"},
+			}}},
+			expected: []string{
+				"new-session", "-ds", "dev", "-n", `odd\;`, "-c", "/code", ";",
+				"new-window", "-t", "dev", "-n", "next", "-c", "/code",
+			},
+		},
+		{
+			name: "grouped session adds all windows to the group",
+			session: models.Session{Name: "web", Path: "/code/web", Group: "api", Layout: models.SessionLayout{Windows: []models.Window{
+				{Name: "editor"},
+				{Name: "run"},
+			}}},
+			expected: []string{
+				"new-session", "-d", "-s", "web", "-t", "api", "-c", "/code/web", ";",
+				"new-window", "-t", "web", "-n", "editor", "-c", "/code/web", ";",
+				"new-window", "-t", "web", "-n", "run", "-c", "/code/web",
+			},
+		},
+		{
+			name: "template is recorded on the session",
+			session: models.Session{Name: "dev", Path: "/code", Template: "go", Layout: models.SessionLayout{Windows: []models.Window{
+				{Name: "main"},
+			}}},
+			expected: []string{
+				"new-session", "-ds", "dev", "-n", "main", "-c", "/code", ";",
+				"set-option", "-t", "dev", "@muxly_template", "go",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildSessionArgs(tt.session)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildSessionArgs() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePanes(t *testing.T) {
+	output := "0::muxly::editor::muxly::b25d,80x24,0,0,1::muxly::/code/api::muxly::nvim::muxly::101\n" +
+		"1::muxly::run::muxly::c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}::muxly::/code/api::muxly::zsh::muxly::102\n" +
+		"1::muxly::run::muxly::c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}::muxly::/code/api/logs::muxly::tail::muxly::103\n"
+
+	result, pids, err := parsePanes(output)
+	if err != nil {
+		t.Fatalf("parsePanes() error = %v", err)
+	}
+	expected := []PaneInfo{
+		{WindowIndex: 0, WindowName: "editor", WindowLayout: "b25d,80x24,0,0,1", Path: "/code/api", Command: "nvim"},
+		{WindowIndex: 1, WindowName: "run", WindowLayout: "c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}", Path: "/code/api", Command: "zsh"},
+		{WindowIndex: 1, WindowName: "run", WindowLayout: "c1f2,80x24,0,0{40x24,0,0,2,39x24,41,0,3}", Path: "/code/api/logs", Command: "tail"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parsePanes() = %+v, want %+v", result, expected)
+	}
+	if want := []int{101, 102, 103}; !slices.Equal(pids, want) {
+		t.Errorf("parsePanes() pids = %v, want %v", pids, want)
+	}
+
+	if _, _, err := parsePanes("0::muxly::editor\n"); err == nil {
+		t.Error("parsePanes() accepted a line with missing fields")
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	output := "api::muxly::/code/api::muxly::3::muxly::1\n" +
+		"my notes::muxly::/home/user/notes::muxly::1::muxly::0\n"
+
+	result, err := parseSessions(output)
+	if err != nil {
+		t.Fatalf("parseSessions() error = %v", err)
+	}
+	expected := []SessionInfo{
+		{Name: "api", Path: "/code/api", Windows: 3, Attached: 1},
+		{Name: "my notes", Path: "/home/user/notes", Windows: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("parseSessions() = %+v, want %+v", result, expected)
+	}
+
+	if _, err := parseSessions("api::muxly::/code/api::muxly::x::muxly::0\n"); err == nil {
+		t.Error("parseSessions() accepted a window count that is not a number")
+	}
+}
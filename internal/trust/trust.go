@@ -0,0 +1,169 @@
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Pairadux/muxly/internal/config"
+	"github.com/Pairadux/muxly/internal/utility"
+)
+
+// FileName is the name of the trust store inside the muxly state directory.
+const FileName = "trust.json"
+
+// Status describes a .muxly file's standing in the store.
+type Status int
+
+const (
+	// Untrusted files have never been trusted.
+	Untrusted Status = iota
+	// Changed files were trusted, but their contents differ since.
+	Changed
+	// Trusted files are trusted with their current contents.
+	Trusted
+)
+
+func (s Status) String() string {
+	switch s {
+	case Trusted:
+		return "trusted"
+	case Changed:
+		return "changed"
+	default:
+		return "untrusted"
+	}
+}
+
+// Entry records the contents a .muxly file was trusted with.
+type Entry struct {
+	Hash      string    `json:"hash"`
+	TrustedAt time.Time `json:"trusted_at"`
+}
+
+// Store is the persistent set of trusted .muxly files, keyed by absolute
+// file path. A file stays trusted only while its contents hash the same, so
+// any edit has to be trusted again.
+type Store struct {
+	path    string
+	Entries map[string]Entry `json:"entries"`
+}
+
+// DefaultPath returns the location of the trust store,
+// $XDG_STATE_HOME/muxly/trust.json.
+func DefaultPath() (string, error) {
+	dir, err := utility.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads the trust store at path. A missing file yields an empty store.
+func Load(path string) (*Store, error) {
+	store := &Store{path: path, Entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return store, fmt.Errorf("reading trust store: %w", err)
+	}
+
+	if err := json.Unmarshal(data, store); err != nil {
+		return &Store{path: path, Entries: make(map[string]Entry)}, fmt.Errorf("parsing trust store %s: %w", path, err)
+	}
+	if store.Entries == nil {
+		store.Entries = make(map[string]Entry)
+	}
+
+	return store, nil
+}
+
+// LoadDefault reads the trust store from DefaultPath.
+func LoadDefault() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return &Store{Entries: make(map[string]Entry)}, err
+	}
+	return Load(path)
+}
+
+// Save writes the store back to the path it was loaded from.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return fmt.Errorf("trust store has no path")
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return utility.WriteFileAtomic(s.path, data)
+}
+
+// Hash returns the hex SHA-256 of a .muxly file's contents.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Status reports whether the .muxly file at path, with contents data, is
+// trusted.
+func (s *Store) Status(path string, data []byte) Status {
+	entry, ok := s.Entries[path]
+	switch {
+	case !ok:
+		return Untrusted
+	case entry.Hash != Hash(data):
+		return Changed
+	default:
+		return Trusted
+	}
+}
+
+// Trust marks the .muxly file at path as trusted with contents data.
+func (s *Store) Trust(path string, data []byte, now time.Time) {
+	s.Entries[path] = Entry{Hash: Hash(data), TrustedAt: now}
+}
+
+// Untrust removes the .muxly file at path from the store, reporting whether
+// it was there.
+func (s *Store) Untrust(path string) bool {
+	_, ok := s.Entries[path]
+	delete(s.Entries, path)
+	return ok
+}
+
+// Paths returns the paths of all trusted files, sorted.
+func (s *Store) Paths() []string {
+	paths := make([]string, 0, len(s.Entries))
+	for path := range s.Entries {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// Allowed reports whether the commands of the .muxly file at path, with
+// contents data, may run without asking: either policy allows every file or
+// the file is trusted as it is.
+func Allowed(policy, path string, data []byte) bool {
+	if policy == config.MuxlyFileAllow {
+		return true
+	}
+
+	store, err := LoadDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return false
+	}
+	return store.Status(path, data) == Trusted
+}
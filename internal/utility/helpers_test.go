@@ -286,3 +286,132 @@ func TestGetSubDirsIgnoreNames(t *testing.T) {
 		}
 	}
 }
+
+func TestScanSubDirsWatch(t *testing.T) {
+	tempDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(tempDir, "project1", "src", "lib"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "project2"), 0755)
+
+	result, err := ScanSubDirs(tempDir, ScanOptions{MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("ScanSubDirs unexpected error: %v", err)
+	}
+
+	watchSet := make(map[string]bool)
+	for _, d := range result.Watch {
+		watchSet[d] = true
+	}
+
+	// The root and every directory whose children were read are watched
+	for _, expected := range []string{
+		tempDir,
+		filepath.Join(tempDir, "project1"),
+		filepath.Join(tempDir, "project2"),
+	} {
+		if !watchSet[expected] {
+			t.Errorf("ScanSubDirs Watch should contain %q", expected)
+		}
+	}
+
+	// Directories at the depth limit are returned but never read
+	if watchSet[filepath.Join(tempDir, "project1", "src")] {
+		t.Errorf("ScanSubDirs Watch should not contain %q", filepath.Join(tempDir, "project1", "src"))
+	}
+}
+
+func TestScanSubDirsMarkers(t *testing.T) {
+	tempDir := t.TempDir()
+
+	os.MkdirAll(filepath.Join(tempDir, "api", ".git"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "api", "internal", "server"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "group", "web"), 0755)
+	os.WriteFile(filepath.Join(tempDir, "group", "web", "package.json"), []byte("{}"), 0644)
+	os.MkdirAll(filepath.Join(tempDir, "group", "docs"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "nested", "sub", ".git"), 0755)
+	os.MkdirAll(filepath.Join(tempDir, "nested", "sub", "inner", ".git"), 0755)
+
+	ignoreNames := models.StringSet{".git": {}}
+	markers := []string{".git", "package.json"}
+
+	tests := []struct {
+		name          string
+		stopAtMarker  bool
+		shouldContain []string
+		shouldExclude []string
+	}{
+		{
+			name:         "markers without stop descend into projects",
+			stopAtMarker: false,
+			shouldContain: []string{
+				filepath.Join(tempDir, "api"),
+				filepath.Join(tempDir, "group", "web"),
+				filepath.Join(tempDir, "nested", "sub"),
+				filepath.Join(tempDir, "nested", "sub", "inner"),
+			},
+			shouldExclude: []string{
+				filepath.Join(tempDir, "group"),
+				filepath.Join(tempDir, "group", "docs"),
+				filepath.Join(tempDir, "api", "internal"),
+			},
+		},
+		{
+			name:         "stop at marker skips nested projects",
+			stopAtMarker: true,
+			shouldContain: []string{
+				filepath.Join(tempDir, "api"),
+				filepath.Join(tempDir, "group", "web"),
+				filepath.Join(tempDir, "nested", "sub"),
+			},
+			shouldExclude: []string{
+				filepath.Join(tempDir, "nested", "sub", "inner"),
+				filepath.Join(tempDir, "api", "internal", "server"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ScanSubDirs(tempDir, ScanOptions{
+				MaxDepth:       5,
+				IgnoreDirNames: ignoreNames,
+				Markers:        markers,
+				StopAtMarker:   tt.stopAtMarker,
+			})
+			if err != nil {
+				t.Fatalf("ScanSubDirs unexpected error: %v", err)
+			}
+
+			dirSet := make(map[string]bool)
+			for _, d := range result.Dirs {
+				dirSet[d] = true
+			}
+
+			for _, expected := range tt.shouldContain {
+				if !dirSet[expected] {
+					t.Errorf("ScanSubDirs should contain %q, got %v", expected, result.Dirs)
+				}
+			}
+			for _, excluded := range tt.shouldExclude {
+				if dirSet[excluded] {
+					t.Errorf("ScanSubDirs should not contain %q", excluded)
+				}
+			}
+		})
+	}
+}
+
+func TestHasMarker(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module x"), 0644)
+
+	if !HasMarker(tempDir, []string{"Cargo.toml", "go.mod"}) {
+		t.Error("HasMarker should find go.mod")
+	}
+	if HasMarker(tempDir, []string{".git"}) {
+		t.Error("HasMarker should not find .git")
+	}
+	if HasMarker(tempDir, nil) {
+		t.Error("HasMarker with no markers should be false")
+	}
+}